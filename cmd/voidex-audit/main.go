@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/core"
+	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/storage"
+)
+
+// voidex-audit recomputes total emission from the stored chain and checks it
+// against the emission schedule and MaxSupply
+func main() {
+	network := flag.String("network", core.NETWORK_MAINNET, "network the data directory belongs to")
+	chainSpec := flag.String("chainspec", "", "JSON chain spec of a custom network, instead of -network")
+	dbPath := flag.String("datadir", "", "blockchain data directory (defaults to the network's)")
+	flag.Parse()
+
+	var params *core.ChainParams
+	var err error
+	if *chainSpec != "" {
+		params, err = core.LoadChainSpec(*chainSpec)
+	} else {
+		params, err = core.GetChainParams(*network)
+	}
+	if err != nil {
+		log.Fatalf("Invalid network: %v", err)
+	}
+	if *dbPath == "" {
+		*dbPath = params.DataDir
+	}
+
+	store, err := storage.NewLevelDBStorage(*dbPath)
+	if err != nil {
+		log.Fatalf("Failed to open storage: %v", err)
+	}
+	defer store.Close()
+
+	if genesis, err := store.GetBlock(0); err != nil || genesis == nil {
+		log.Fatalf("No chain found in %s", *dbPath)
+	}
+
+	blockchain, err := core.NewBlockchain(store, params)
+	if err != nil {
+		log.Fatalf("Failed to load blockchain: %v", err)
+	}
+	defer blockchain.Close()
+
+	audit, err := blockchain.AuditEmission()
+	if err != nil {
+		log.Fatalf("Audit failed: %v", err)
+	}
+
+	fmt.Printf("Height:           %d\n", audit.Height)
+	fmt.Printf("Actual supply:    %d\n", audit.ActualSupply)
+	fmt.Printf("Scheduled supply: %d\n", audit.ScheduledSupply)
+	fmt.Printf("Max supply:       %d\n", audit.MaxSupply)
+	for _, violation := range audit.Violations {
+		fmt.Printf("VIOLATION: %s\n", violation)
+	}
+
+	if !audit.OK() {
+		fmt.Println("Emission audit FAILED")
+		blockchain.Close()
+		store.Close()
+		os.Exit(1)
+	}
+
+	fmt.Println("Emission audit passed")
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/core"
+)
+
+// voidex-genesis runs the genesis ceremony: it builds the genesis block from a
+// config file, mines it to the initial target and prints the canonical hash.
+// With -out, the config is written back with GenesisNonce and GenesisHash pinned.
+func main() {
+	configPath := flag.String("config", "", "genesis config file (JSON)")
+	outPath := flag.String("out", "", "write the pinned genesis config to this file")
+	flag.Parse()
+
+	if *configPath == "" {
+		log.Fatalf("Usage: voidex-genesis -config genesis.json [-out pinned.json]")
+	}
+
+	cfg, err := core.ReadGenesisConfig(*configPath)
+	if err != nil {
+		log.Fatalf("Failed to load genesis config: %v", err)
+	}
+
+	fmt.Printf("Mining genesis block for %s...\n", cfg.ChainID)
+	block, err := core.MineGenesisBlock(cfg)
+	if err != nil {
+		log.Fatalf("Failed to mine genesis block: %v", err)
+	}
+
+	if cfg.GenesisHash != "" && cfg.GenesisHash != block.BlockHash {
+		fmt.Printf("WARNING: config pins %s, ceremony produced a different hash\n", cfg.GenesisHash)
+	}
+
+	cfg.GenesisNonce = block.Nonce
+	cfg.GenesisHash = block.BlockHash
+
+	fmt.Printf("Genesis nonce: %d\n", block.Nonce)
+	fmt.Printf("Genesis hash:  %s\n", block.BlockHash)
+
+	if *outPath != "" {
+		data, err := json.MarshalIndent(cfg, "", "  ")
+		if err != nil {
+			log.Fatalf("Failed to encode genesis config: %v", err)
+		}
+		if err := os.WriteFile(*outPath, data, 0644); err != nil {
+			log.Fatalf("Failed to write genesis config: %v", err)
+		}
+		fmt.Printf("Pinned genesis config written to %s\n", *outPath)
+	}
+}
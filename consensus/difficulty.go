@@ -4,16 +4,29 @@ import (
 	"fmt"
 )
 
+// DifficultyAlgorithm names how the difficulty is retargeted
+type DifficultyAlgorithm string
+
+const (
+	// DifficultyRetarget rescales difficulty every DifficultyWindow blocks
+	// from the time the window took to mine
+	DifficultyRetarget DifficultyAlgorithm = "retarget"
+	// DifficultyFixed keeps the initial difficulty forever
+	DifficultyFixed DifficultyAlgorithm = "fixed"
+)
+
 // DifficultyAdjuster handles difficulty recalculation
 type DifficultyAdjuster struct {
+	Algorithm          DifficultyAlgorithm
 	DifficultyWindow   uint32
 	TargetBlockTime    uint32 // seconds
 	AdjustmentInterval int64  // nanoseconds
 }
 
 // NewDifficultyAdjuster creates a new adjuster
-func NewDifficultyAdjuster(window, targetTime uint32) *DifficultyAdjuster {
+func NewDifficultyAdjuster(algorithm DifficultyAlgorithm, window, targetTime uint32) *DifficultyAdjuster {
 	return &DifficultyAdjuster{
+		Algorithm:          algorithm,
 		DifficultyWindow:   window,
 		TargetBlockTime:    targetTime,
 		AdjustmentInterval: int64(targetTime) * int64(window) * int64(1e9),
@@ -63,5 +76,23 @@ func (da *DifficultyAdjuster) AdjustDifficulty(
 
 // ShouldAdjustDifficulty checks if we're at an adjustment point
 func (da *DifficultyAdjuster) ShouldAdjustDifficulty(blockHeight uint64) bool {
+	if da.Algorithm == DifficultyFixed {
+		return false
+	}
 	return blockHeight > 0 && blockHeight%uint64(da.DifficultyWindow) == 0
 }
+
+// ValidateDifficultyAlgorithm checks that an algorithm is known and has the
+// parameters it needs. An empty algorithm means DifficultyRetarget.
+func ValidateDifficultyAlgorithm(algorithm DifficultyAlgorithm, window, targetTime uint32) error {
+	switch algorithm {
+	case "", DifficultyRetarget:
+		if window == 0 || targetTime == 0 {
+			return fmt.Errorf("retarget needs a positive difficulty window and target block time")
+		}
+	case DifficultyFixed:
+	default:
+		return fmt.Errorf("unknown difficulty algorithm: %q", algorithm)
+	}
+	return nil
+}
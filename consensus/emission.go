@@ -0,0 +1,178 @@
+package consensus
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// EmissionCurve names the shape of the block subsidy over time
+type EmissionCurve string
+
+const (
+	// EmissionHalving halves the subsidy every HalvingInterval blocks
+	EmissionHalving EmissionCurve = "halving"
+	// EmissionDecay multiplies the subsidy by DecayNumerator/DecayDenominator every DecayInterval blocks
+	EmissionDecay EmissionCurve = "decay"
+	// EmissionStep pays a fixed subsidy per height range
+	EmissionStep EmissionCurve = "step"
+)
+
+// RewardStep sets the subsidy for blocks from StartHeight until the next step
+type RewardStep struct {
+	StartHeight uint64
+	Reward      uint64
+}
+
+// EmissionSchedule describes how the block subsidy evolves with height
+type EmissionSchedule struct {
+	Curve            EmissionCurve
+	InitialReward    uint64       // halving, decay: subsidy at height 0
+	HalvingInterval  uint64       // halving: blocks between halvings
+	DecayInterval    uint64       // decay: blocks between reductions
+	DecayNumerator   uint64       // decay: subsidy is multiplied by Numerator/Denominator
+	DecayDenominator uint64       // each interval
+	Steps            []RewardStep // step: ordered by StartHeight, first at 0
+	TailEmission     uint64       // Floor the subsidy never drops below (0 = none)
+}
+
+// Validate checks that the schedule is well formed
+func (es *EmissionSchedule) Validate() error {
+	switch es.Curve {
+	case EmissionHalving:
+		if es.HalvingInterval == 0 {
+			return fmt.Errorf("halving interval must be positive")
+		}
+	case EmissionDecay:
+		if es.DecayInterval == 0 {
+			return fmt.Errorf("decay interval must be positive")
+		}
+		if es.DecayDenominator == 0 || es.DecayNumerator >= es.DecayDenominator {
+			return fmt.Errorf("decay factor must be below 1, got %d/%d", es.DecayNumerator, es.DecayDenominator)
+		}
+	case EmissionStep:
+		if len(es.Steps) == 0 || es.Steps[0].StartHeight != 0 {
+			return fmt.Errorf("step table must start at height 0")
+		}
+		for i := 1; i < len(es.Steps); i++ {
+			if es.Steps[i].StartHeight <= es.Steps[i-1].StartHeight {
+				return fmt.Errorf("step table heights must be increasing at step %d", i)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown emission curve: %q", es.Curve)
+	}
+	return nil
+}
+
+// RewardAt returns the uncapped subsidy at a height
+func (es *EmissionSchedule) RewardAt(height uint64) uint64 {
+	reward := uint64(0)
+
+	switch es.Curve {
+	case EmissionHalving:
+		// Shifting a uint64 by 64 or more yields 0, so no cutoff is needed
+		reward = es.InitialReward >> (height / es.HalvingInterval)
+	case EmissionDecay:
+		reward = es.InitialReward
+		for epoch := height / es.DecayInterval; epoch > 0 && reward > es.TailEmission; epoch-- {
+			reward = es.decay(reward)
+		}
+	case EmissionStep:
+		for _, step := range es.Steps {
+			if step.StartHeight > height {
+				break
+			}
+			reward = step.Reward
+		}
+	}
+
+	if reward < es.TailEmission {
+		return es.TailEmission
+	}
+	return reward
+}
+
+// SupplyBefore returns the uncapped subsidy of the first n blocks (heights 0..n-1).
+// It sums whole constant-reward segments, so the cost grows with the number of
+// reward changes rather than with n.
+func (es *EmissionSchedule) SupplyBefore(n uint64) uint64 {
+	supply := uint64(0)
+
+	switch es.Curve {
+	case EmissionHalving:
+		for start, reward := uint64(0), es.InitialReward; start < n; reward >>= 1 {
+			if reward <= es.TailEmission {
+				return addSaturating(supply, mulSaturating(n-start, es.TailEmission))
+			}
+			end := minUint64(addSaturating(start, es.HalvingInterval), n)
+			supply = addSaturating(supply, mulSaturating(end-start, reward))
+			start = end
+		}
+	case EmissionDecay:
+		for start, reward := uint64(0), es.InitialReward; start < n; reward = es.decay(reward) {
+			if reward <= es.TailEmission {
+				return addSaturating(supply, mulSaturating(n-start, es.TailEmission))
+			}
+			end := minUint64(addSaturating(start, es.DecayInterval), n)
+			supply = addSaturating(supply, mulSaturating(end-start, reward))
+			start = end
+		}
+	case EmissionStep:
+		for i, step := range es.Steps {
+			if step.StartHeight >= n {
+				break
+			}
+			end := n
+			if i+1 < len(es.Steps) && es.Steps[i+1].StartHeight < n {
+				end = es.Steps[i+1].StartHeight
+			}
+			reward := step.Reward
+			if reward < es.TailEmission {
+				reward = es.TailEmission
+			}
+			supply = addSaturating(supply, mulSaturating(end-step.StartHeight, reward))
+		}
+	}
+
+	return supply
+}
+
+// NextChangeHeight returns the first height above height where the scheduled
+// subsidy changes, or 0 if it never changes again
+func (es *EmissionSchedule) NextChangeHeight(height uint64) uint64 {
+	switch es.Curve {
+	case EmissionHalving:
+		if es.RewardAt(height) == es.TailEmission {
+			return 0
+		}
+		return (height/es.HalvingInterval + 1) * es.HalvingInterval
+	case EmissionDecay:
+		if es.RewardAt(height) == es.TailEmission {
+			return 0
+		}
+		return (height/es.DecayInterval + 1) * es.DecayInterval
+	case EmissionStep:
+		for _, step := range es.Steps {
+			if step.StartHeight > height {
+				return step.StartHeight
+			}
+		}
+	}
+	return 0
+}
+
+// decay applies one decay interval to reward without overflowing
+func (es *EmissionSchedule) decay(reward uint64) uint64 {
+	// reward*num < reward*den, so the high word is always below den
+	hi, lo := bits.Mul64(reward, es.DecayNumerator)
+	quotient, _ := bits.Div64(hi, lo, es.DecayDenominator)
+	return quotient
+}
+
+// minUint64 returns the smaller of two values
+func minUint64(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -16,9 +16,15 @@ type ProofOfWork struct {
 
 // NewProofOfWork creates a new PoW engine
 func NewProofOfWork(difficulty uint32) *ProofOfWork {
-	// Difficulty target (lower = harder)
+	// Difficulty target (lower = harder). Values above 256 used to wrap the
+	// shift into a huge target that accepted any hash; keep that behaviour
+	// without allocating it.
+	shift := uint(256)
+	if difficulty <= 256 {
+		shift = uint(256 - difficulty)
+	}
 	target := big.NewInt(1)
-	target.Lsh(target, uint(256-difficulty))
+	target.Lsh(target, shift)
 
 	return &ProofOfWork{
 		Target:   target,
@@ -1,44 +1,53 @@
 package consensus
 
-// BlockRewardCalculator manages block rewards and halving
+// BlockRewardCalculator manages block rewards and the emission schedule.
+// Rewards are a pure function of height and the chain params, so the same
+// height always yields the same reward regardless of replay order or reorgs.
 type BlockRewardCalculator struct {
-	InitialReward       uint64
-	HalvingInterval     uint64
-	MaxSupply           uint64
-	TotalMinedCoins     uint64
+	Schedule  EmissionSchedule
+	MaxSupply uint64 // 0 = uncapped
 }
 
 // NewBlockRewardCalculator creates reward calculator
-func NewBlockRewardCalculator(initialReward, halvingInterval, maxSupply uint64) *BlockRewardCalculator {
+func NewBlockRewardCalculator(schedule EmissionSchedule, maxSupply uint64) *BlockRewardCalculator {
 	return &BlockRewardCalculator{
-		InitialReward:   initialReward,
-		HalvingInterval: halvingInterval,
-		MaxSupply:       maxSupply,
-		TotalMinedCoins: 0,
+		Schedule:  schedule,
+		MaxSupply: maxSupply,
 	}
 }
 
 // GetBlockReward calculates reward for a given block height
 func (brc *BlockRewardCalculator) GetBlockReward(blockHeight uint64) uint64 {
-	halvings := blockHeight / brc.HalvingInterval
-	if halvings >= 64 { // Prevent shift overflow
-		return 0
+	reward := brc.Schedule.RewardAt(blockHeight)
+	if brc.MaxSupply == 0 {
+		return reward
 	}
 
-	reward := brc.InitialReward >> halvings
-
 	// Check if total supply would exceed max
-	if brc.TotalMinedCoins+reward > brc.MaxSupply {
-		return brc.MaxSupply - brc.TotalMinedCoins
+	issued := brc.Schedule.SupplyBefore(blockHeight)
+	if issued >= brc.MaxSupply {
+		return 0
+	}
+	if reward > brc.MaxSupply-issued {
+		return brc.MaxSupply - issued
 	}
 
 	return reward
 }
 
-// GetHalvingHeight returns the block height of the next halving
-func (brc *BlockRewardCalculator) GetHalvingHeight(blockHeight uint64) uint64 {
-	halvings := blockHeight / brc.HalvingInterval
-	return (halvings + 1) * brc.HalvingInterval
+// GetSupplyAtHeight returns the total subsidy issued by blocks 0 through blockHeight
+func (brc *BlockRewardCalculator) GetSupplyAtHeight(blockHeight uint64) uint64 {
+	supply := brc.Schedule.SupplyBefore(addSaturating(blockHeight, 1))
+	if brc.MaxSupply > 0 && supply > brc.MaxSupply {
+		return brc.MaxSupply
+	}
+	return supply
+}
+
+// GetNextRewardChangeHeight returns the height of the next subsidy change
+// (the next halving on a halving curve), or 0 if the subsidy never changes again
+func (brc *BlockRewardCalculator) GetNextRewardChangeHeight(blockHeight uint64) uint64 {
+	return brc.Schedule.NextChangeHeight(blockHeight)
 }
 
 // GetTotalSupply returns total coins that will ever exist
@@ -52,27 +61,35 @@ func (brc *BlockRewardCalculator) GetCoinbaseReward(blockHeight uint64, fees uin
 	return blockReward + fees
 }
 
-// UpdateTotalMined records mined coins
-func (brc *BlockRewardCalculator) UpdateTotalMined(amount uint64) {
-	brc.TotalMinedCoins += amount
-	if brc.TotalMinedCoins > brc.MaxSupply {
-		brc.TotalMinedCoins = brc.MaxSupply
-	}
-}
-
-// GetMinedPercentage returns percentage of total supply mined
-func (brc *BlockRewardCalculator) GetMinedPercentage() float64 {
+// GetMinedPercentage returns percentage of total supply mined up to a height
+func (brc *BlockRewardCalculator) GetMinedPercentage(blockHeight uint64) float64 {
 	if brc.MaxSupply == 0 {
 		return 0
 	}
-	return float64(brc.TotalMinedCoins) / float64(brc.MaxSupply) * 100
+	return float64(brc.GetSupplyAtHeight(blockHeight)) / float64(brc.MaxSupply) * 100
 }
 
-// GetBlocksUntilNextHalving returns blocks until next reward halving
-func (brc *BlockRewardCalculator) GetBlocksUntilNextHalving(blockHeight uint64) uint64 {
-	nextHalving := brc.GetHalvingHeight(blockHeight)
-	if nextHalving > blockHeight {
-		return nextHalving - blockHeight
+// GetBlocksUntilNextRewardChange returns blocks until the subsidy next changes
+func (brc *BlockRewardCalculator) GetBlocksUntilNextRewardChange(blockHeight uint64) uint64 {
+	nextChange := brc.GetNextRewardChangeHeight(blockHeight)
+	if nextChange > blockHeight {
+		return nextChange - blockHeight
 	}
 	return 0
 }
+
+// addSaturating adds without wrapping past the maximum uint64
+func addSaturating(a, b uint64) uint64 {
+	if a > ^uint64(0)-b {
+		return ^uint64(0)
+	}
+	return a + b
+}
+
+// mulSaturating multiplies without wrapping past the maximum uint64
+func mulSaturating(a, b uint64) uint64 {
+	if a != 0 && b > ^uint64(0)/a {
+		return ^uint64(0)
+	}
+	return a * b
+}
@@ -0,0 +1,198 @@
+package consensus
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Block versions signal deployments when their top three bits are 001; the
+// remaining 29 bits each signal one deployment
+const (
+	VersionBitsTopBits = uint32(0x20000000)
+	VersionBitsTopMask = uint32(0xE0000000)
+	VersionBitsMaxBit  = 28
+)
+
+// DeploymentState is the state of a version bits deployment for a block
+type DeploymentState string
+
+const (
+	// DeploymentDefined is the initial state, before StartHeight
+	DeploymentDefined DeploymentState = "defined"
+	// DeploymentStarted means miners may signal for the deployment
+	DeploymentStarted DeploymentState = "started"
+	// DeploymentLockedIn means a window reached the threshold; the deployment
+	// activates at the next window
+	DeploymentLockedIn DeploymentState = "locked_in"
+	// DeploymentActive means the deployment's rules are enforced
+	DeploymentActive DeploymentState = "active"
+	// DeploymentFailed means the timeout passed without locking in
+	DeploymentFailed DeploymentState = "failed"
+)
+
+// Deployment is a soft fork activated by miners signaling a version bit
+type Deployment struct {
+	Name          string
+	Bit           uint8  // Version bit miners set to signal
+	StartHeight   uint64 // Signaling counts from the first window starting at or after this height
+	TimeoutHeight uint64 // Fails if not locked in by the first window starting at or after this height
+	Threshold     uint32 // Signaling blocks needed in one window to lock in
+}
+
+// Validate checks that the deployment is well formed for a signaling window
+func (d *Deployment) Validate(window uint32) error {
+	if d.Name == "" {
+		return fmt.Errorf("deployment name is required")
+	}
+	if d.Bit > VersionBitsMaxBit {
+		return fmt.Errorf("deployment %s: bit %d out of range", d.Name, d.Bit)
+	}
+	if d.TimeoutHeight <= d.StartHeight {
+		return fmt.Errorf("deployment %s: timeout must be after start", d.Name)
+	}
+	if d.Threshold == 0 || d.Threshold > window {
+		return fmt.Errorf("deployment %s: threshold %d must be between 1 and the window size %d",
+			d.Name, d.Threshold, window)
+	}
+	return nil
+}
+
+// Mask returns the version bit the deployment signals with
+func (d *Deployment) Mask() uint32 {
+	return uint32(1) << d.Bit
+}
+
+// Signals reports whether a block version signals for the deployment
+func (d *Deployment) Signals(version uint32) bool {
+	return version&VersionBitsTopMask == VersionBitsTopBits && version&d.Mask() != 0
+}
+
+// DeploymentCache remembers a deployment's state at each window boundary, so
+// each window's signals are only counted once. It is safe for concurrent use.
+type DeploymentCache struct {
+	mutex  sync.Mutex
+	states map[uint64]DeploymentState
+}
+
+// NewDeploymentCache creates an empty cache
+func NewDeploymentCache() *DeploymentCache {
+	return &DeploymentCache{states: make(map[uint64]DeploymentState)}
+}
+
+// get returns the cached state at a window boundary
+func (c *DeploymentCache) get(boundary uint64) (DeploymentState, bool) {
+	if c == nil {
+		return "", false
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	state, ok := c.states[boundary]
+	return state, ok
+}
+
+// put caches the state at a window boundary
+func (c *DeploymentCache) put(boundary uint64, state DeploymentState) {
+	if c == nil {
+		return
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.states[boundary] = state
+}
+
+// Forget drops the states that depend on the block at height or later, which
+// must be called when that block is disconnected
+func (c *DeploymentCache) Forget(height uint64) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for boundary := range c.states {
+		if boundary > height {
+			delete(c.states, boundary)
+		}
+	}
+}
+
+// StateAt returns the deployment's state for a block at height. The state only
+// changes at window boundaries, based on the window that just ended;
+// versionAt returns the version of the block at an earlier height. States are
+// taken from and added to cache, which may be nil.
+func (d *Deployment) StateAt(height uint64, window uint32, versionAt func(uint64) uint32, cache *DeploymentCache) DeploymentState {
+	size := uint64(window)
+	state := DeploymentDefined
+
+	// Walk back to the latest boundary with a known state
+	var pending []uint64
+	for boundary := height - height%size; boundary > 0; boundary -= size {
+		if cached, ok := cache.get(boundary); ok {
+			state = cached
+			break
+		}
+		pending = append(pending, boundary)
+	}
+
+	// Then evaluate the remaining windows oldest first
+	for i := len(pending) - 1; i >= 0; i-- {
+		boundary := pending[i]
+		state = d.nextState(state, boundary, size, versionAt)
+		cache.put(boundary, state)
+	}
+
+	return state
+}
+
+// nextState returns the state at boundary given the state of the window
+// that ends there
+func (d *Deployment) nextState(state DeploymentState, boundary, size uint64, versionAt func(uint64) uint32) DeploymentState {
+	switch state {
+	case DeploymentDefined:
+		if boundary >= d.TimeoutHeight {
+			return DeploymentFailed
+		} else if boundary >= d.StartHeight {
+			return DeploymentStarted
+		}
+	case DeploymentStarted:
+		if d.countSignals(boundary-size, boundary, versionAt) >= d.Threshold {
+			return DeploymentLockedIn
+		} else if boundary >= d.TimeoutHeight {
+			return DeploymentFailed
+		}
+	case DeploymentLockedIn:
+		return DeploymentActive
+	}
+	return state
+}
+
+// SignalCount returns how many blocks of the window containing height, up to
+// but not including height, signal for the deployment
+func (d *Deployment) SignalCount(height uint64, window uint32, versionAt func(uint64) uint32) uint32 {
+	return d.countSignals(height-height%uint64(window), height, versionAt)
+}
+
+// countSignals counts signaling blocks in [start, end)
+func (d *Deployment) countSignals(start, end uint64, versionAt func(uint64) uint32) uint32 {
+	count := uint32(0)
+	for h := start; h < end; h++ {
+		if d.Signals(versionAt(h)) {
+			count++
+		}
+	}
+	return count
+}
+
+// ComputeBlockVersion returns the version a new block at height should carry:
+// the version bits prefix plus the bit of every deployment still collecting
+// signals (started or locked in). caches, if given, holds one cache per deployment.
+func ComputeBlockVersion(deployments []Deployment, caches []*DeploymentCache, height uint64, window uint32, versionAt func(uint64) uint32) uint32 {
+	version := VersionBitsTopBits
+	for i := range deployments {
+		var cache *DeploymentCache
+		if i < len(caches) {
+			cache = caches[i]
+		}
+		switch deployments[i].StateAt(height, window, versionAt, cache) {
+		case DeploymentStarted, DeploymentLockedIn:
+			version |= deployments[i].Mask()
+		}
+	}
+	return version
+}
@@ -0,0 +1,57 @@
+package consensus
+
+import (
+	"testing"
+)
+
+func TestDeploymentStateCached(t *testing.T) {
+	const window = 10
+	deployment := Deployment{Name: "test", Bit: 1, StartHeight: 10, TimeoutHeight: 100, Threshold: 8}
+
+	// Signal from height 30 on, so the deployment locks in at 40 and activates at 50
+	versions := make([]uint32, 100)
+	for h := range versions {
+		versions[h] = VersionBitsTopBits
+		if h >= 30 {
+			versions[h] |= deployment.Mask()
+		}
+	}
+	calls := 0
+	versionAt := func(h uint64) uint32 {
+		calls++
+		return versions[h]
+	}
+
+	cache := NewDeploymentCache()
+	for height := uint64(0); height < 100; height++ {
+		cached := deployment.StateAt(height, window, versionAt, cache)
+		if uncached := deployment.StateAt(height, window, versionAt, nil); cached != uncached {
+			t.Fatalf("height %d: cached state %s, uncached %s", height, cached, uncached)
+		}
+	}
+	if state := deployment.StateAt(45, window, versionAt, cache); state != DeploymentLockedIn {
+		t.Fatalf("state at 45 is %s", state)
+	}
+	if state := deployment.StateAt(50, window, versionAt, cache); state != DeploymentActive {
+		t.Fatalf("state at 50 is %s", state)
+	}
+
+	// Every window is counted already, so cached lookups read no block versions
+	calls = 0
+	deployment.StateAt(99, window, versionAt, cache)
+	if calls != 0 {
+		t.Fatalf("cached lookup read %d block versions", calls)
+	}
+
+	// Without the signals, forgotten windows are counted again and never lock in
+	cache.Forget(30)
+	for h := 30; h < 100; h++ {
+		versions[h] = VersionBitsTopBits
+	}
+	if state := deployment.StateAt(99, window, versionAt, cache); state != DeploymentStarted {
+		t.Fatalf("state after forgetting is %s", state)
+	}
+	if state := deployment.StateAt(100, window, versionAt, cache); state != DeploymentFailed {
+		t.Fatalf("state at timeout is %s", state)
+	}
+}
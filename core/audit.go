@@ -0,0 +1,77 @@
+package core
+
+import (
+	"fmt"
+)
+
+// EmissionAudit summarizes coins actually created by the chain
+type EmissionAudit struct {
+	Height          uint64   // Height of the audited tip
+	ActualSupply    uint64   // Coins created by coinbases, excluding recycled fees
+	ScheduledSupply uint64   // Genesis allocations plus scheduled subsidy up to the tip
+	MaxSupply       uint64   // Consensus supply cap (0 = uncapped)
+	Violations      []string // Blocks whose coinbase created more than allowed
+}
+
+// OK reports whether the chain stayed within its schedule and the supply cap
+func (ea *EmissionAudit) OK() bool {
+	return len(ea.Violations) == 0 &&
+		ea.ActualSupply <= ea.ScheduledSupply &&
+		(ea.MaxSupply == 0 || ea.ActualSupply <= ea.MaxSupply)
+}
+
+// AuditEmission replays every block, recomputing the coins each coinbase created
+// (coinbase outputs minus fees) and checking them against the emission schedule
+func (bc *Blockchain) AuditEmission() (*EmissionAudit, error) {
+	bc.mutex.RLock()
+	defer bc.mutex.RUnlock()
+
+	if len(bc.Blocks) == 0 {
+		return nil, fmt.Errorf("chain is empty")
+	}
+
+	tip := bc.Blocks[len(bc.Blocks)-1]
+	audit := &EmissionAudit{
+		Height:          tip.Height,
+		ScheduledSupply: bc.RewardCalculator.GetSupplyAtHeight(tip.Height) + bc.GenesisConfig.TotalAllocated(),
+		MaxSupply:       bc.GenesisConfig.MaxSupply,
+	}
+
+	utxoSet := NewUTXOSet()
+	for _, block := range bc.Blocks {
+		if len(block.Transactions) == 0 || !block.Transactions[0].IsCoinbase() {
+			return nil, fmt.Errorf("block #%d has no coinbase", block.Height)
+		}
+
+		// Apply transactions in order so in-block spends are priced correctly
+		fees := uint64(0)
+		for i, tx := range block.Transactions {
+			if i > 0 {
+				inputTotal := tx.GetTotalInput(utxoSet)
+				outputTotal := tx.GetTotalOutput()
+				if inputTotal > outputTotal {
+					fees += inputTotal - outputTotal
+				}
+			}
+			connectTransaction(utxoSet, tx, block.Height)
+		}
+
+		coinbaseTotal := block.Transactions[0].GetTotalOutput()
+		minted := uint64(0)
+		if coinbaseTotal > fees {
+			minted = coinbaseTotal - fees
+		}
+
+		subsidy := bc.RewardCalculator.GetBlockReward(block.Height)
+		if block.Height == 0 {
+			subsidy += bc.GenesisConfig.TotalAllocated()
+		}
+		if minted > subsidy {
+			audit.Violations = append(audit.Violations,
+				fmt.Sprintf("block #%d created %d, subsidy is %d", block.Height, minted, subsidy))
+		}
+		audit.ActualSupply += minted
+	}
+
+	return audit, nil
+}
@@ -1,10 +1,14 @@
 package core
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"time"
+
+	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/consensus"
 )
 
 // Block represents a blockchain block
@@ -39,6 +43,49 @@ func NewBlock(prevHash string, transactions []*Transaction, difficulty uint32, h
 	return block
 }
 
+// Serialize encodes the block in its canonical binary form: the header fields
+// followed by every transaction's canonical encoding. Miner isn't committed to
+// by the block hash, so it is left out; otherwise anyone relaying the block
+// could change its size.
+func (b *Block) Serialize() []byte {
+	var buf bytes.Buffer
+
+	binary.Write(&buf, binary.LittleEndian, b.Version)
+	writeBytes(&buf, []byte(b.PrevBlockHash))
+	writeBytes(&buf, []byte(b.MerkleRoot))
+	binary.Write(&buf, binary.LittleEndian, b.Timestamp)
+	binary.Write(&buf, binary.LittleEndian, b.Difficulty)
+	binary.Write(&buf, binary.LittleEndian, b.Nonce)
+	binary.Write(&buf, binary.LittleEndian, b.Height)
+
+	binary.Write(&buf, binary.LittleEndian, uint32(len(b.Transactions)))
+	for _, tx := range b.Transactions {
+		buf.Write(tx.Serialize())
+	}
+
+	return buf.Bytes()
+}
+
+// Size returns the canonical serialized size in bytes
+func (b *Block) Size() int {
+	return len(b.Serialize())
+}
+
+// SetExtraNonce changes the coinbase extra nonce and rehashes the block, giving
+// miners a fresh header once the nonce space is exhausted
+func (b *Block) SetExtraNonce(extraNonce uint64) {
+	if len(b.Transactions) == 0 || !b.Transactions[0].IsCoinbase() {
+		return
+	}
+
+	coinbase := b.Transactions[0]
+	coinbase.Inputs[0].ExtraNonce = extraNonce
+	coinbase.TxHash = coinbase.CalculateHash()
+
+	b.MerkleRoot = b.CalculateMerkleRoot()
+	b.BlockHash = b.CalculateHash()
+}
+
 // CalculateMerkleRoot computes merkle tree root
 func (b *Block) CalculateMerkleRoot() string {
 	if len(b.Transactions) == 0 {
@@ -86,6 +133,24 @@ func (b *Block) CalculateHash() string {
 	return hex.EncodeToString(hash[:])
 }
 
+// Mine searches nonces, starting at the current one, until the block hash
+// meets the block's difficulty target
+func (b *Block) Mine() error {
+	pow := consensus.NewProofOfWork(b.Difficulty)
+
+	for nonce := b.Nonce; ; nonce++ {
+		b.Nonce = nonce
+		b.BlockHash = b.CalculateHash()
+		if pow.Validate(b.BlockHash) {
+			return nil
+		}
+
+		if nonce == pow.MaxNonce {
+			return fmt.Errorf("max nonce reached without finding valid proof")
+		}
+	}
+}
+
 // IsGenesisBlock checks if this is the genesis block
 func (b *Block) IsGenesisBlock() bool {
 	return b.Height == 0 && b.PrevBlockHash == "0"
@@ -0,0 +1,109 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestBlockSizeIgnoresMiner(t *testing.T) {
+	bc := newTestChain(t, "")
+
+	block, err := bc.NewBlockTemplate("miner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	size, hash := block.Size(), block.CalculateHash()
+
+	block.Miner = "a-much-longer-miner-address-set-by-a-relaying-peer"
+	if block.CalculateHash() != hash {
+		t.Fatal("miner changed the block hash")
+	}
+	if block.Size() != size {
+		t.Fatalf("miner changed the block size from %d to %d", size, block.Size())
+	}
+}
+
+func TestBlockSizeLimit(t *testing.T) {
+	params := RegtestParams()
+	params.Genesis.MaxTxPerBlock = 2
+	bc, err := NewBlockchain(newMemStorage(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Close()
+	blocks := generate(t, bc, 101, "miner")
+
+	block, err := bc.NewBlockTemplate("miner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		coinbase := blocks[i].Transactions[0]
+		block.Transactions = append(block.Transactions,
+			spend(coinbase, []uint32{0}, "alice", coinbase.Outputs[0].Value-1000))
+	}
+	if err := bc.AddBlock(solve(t, block)); err == nil {
+		t.Fatal("block over the transaction limit accepted")
+	}
+
+	// Limit the size to the block as mined, then make it one byte too large
+	block.Transactions = block.Transactions[:2]
+	bc.GenesisConfig.MaxBlockSize = uint32(block.Size())
+	block.Transactions[1].Outputs[0].Address += "x"
+	block.Transactions[1].TxHash = block.Transactions[1].CalculateHash()
+	if err := bc.AddBlock(solve(t, block)); err == nil {
+		t.Fatal("block over the size limit accepted")
+	}
+
+	tx := block.Transactions[1]
+	tx.Outputs[0].Address = "alice"
+	tx.TxHash = tx.CalculateHash()
+	if err := bc.AddBlock(solve(t, block)); err != nil {
+		t.Fatalf("block at the size limit rejected: %v", err)
+	}
+}
+
+func TestTemplateReservesMinerOutputWithoutSubsidy(t *testing.T) {
+	params := RegtestParams()
+	params.Genesis.MaxSupply = 10 * 100000000
+	for i := 0; i < 10; i++ {
+		params.Genesis.Allocations = append(params.Genesis.Allocations,
+			GenesisAllocation{Address: "alice", Amount: 100000000})
+	}
+	bc, err := NewBlockchain(newMemStorage(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Close()
+
+	// Without subsidy or fees the coinbase has no miner output
+	empty, err := bc.NewBlockTemplate("miner")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	genesis := bc.Blocks[0].Transactions[0]
+	var txSize int
+	for i, output := range genesis.Outputs {
+		tx := spend(genesis, []uint32{uint32(i)}, "bob", output.Value-10000)
+		if err := bc.AddPendingTransaction(tx); err != nil {
+			t.Fatal(err)
+		}
+		txSize = tx.Size()
+	}
+
+	// Room for three transactions only if the miner output is forgotten
+	bc.GenesisConfig.MaxBlockSize = uint32(empty.Size() + 3*txSize)
+	block, err := bc.NewBlockTemplate("miner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := block.Mine(); err != nil {
+		t.Fatal(err)
+	}
+	if err := bc.AddBlock(block); err != nil {
+		t.Fatalf("template over the size limit: %v", err)
+	}
+	if len(block.Transactions) != 3 {
+		t.Fatalf("template holds %d transactions", len(block.Transactions))
+	}
+}
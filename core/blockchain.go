@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -16,45 +17,102 @@ type Blockchain struct {
 	UTXOSet            *UTXOSet
 	Difficulty         uint32
 	PendingTransactions *Mempool
+	Orphans            *OrphanPool
+	FeeEstimator       *FeeEstimator
 	Chain              storage.Storage
 	RewardCalculator   *consensus.BlockRewardCalculator
 	DifficultyAdjuster *consensus.DifficultyAdjuster
 	GenesisConfig      *GenesisConfig
+	Params             *ChainParams
+	TimeSource         TimeSource
 	BlockTimestamps    []int64
+	undo               []blockUndo // Outputs spent by each block, for disconnecting it
+	deploymentCaches   []*consensus.DeploymentCache // Per deployment, states at window boundaries
+
+	quit      chan struct{}
+	closeOnce sync.Once
+	wg        sync.WaitGroup
 }
 
-// NewBlockchain creates a new blockchain
-func NewBlockchain(store storage.Storage, minerAddress string) (*Blockchain, error) {
-	genesis := GetMainnetGenesis()
+// NewBlockchain creates a new blockchain for the network described by params
+func NewBlockchain(store storage.Storage, params *ChainParams) (*Blockchain, error) {
+	genesis := params.Genesis
+	if err := genesis.Validate(); err != nil {
+		return nil, err
+	}
+
+	mempool, err := NewMempool(DefaultMempoolPolicy())
+	if err != nil {
+		return nil, err
+	}
 
 	bc := &Blockchain{
 		Blocks:             make([]*Block, 0),
 		UTXOSet:            NewUTXOSet(),
-		Difficulty:         0x00000FFF,
-		PendingTransactions: NewMempool(10000, 24*time.Hour),
+		Difficulty:         genesis.InitialDifficulty,
+		PendingTransactions: mempool,
+		Orphans:            NewOrphanPool(100, 20, 20*time.Minute),
+		FeeEstimator:       NewFeeEstimator(),
 		Chain:              store,
-		RewardCalculator:   consensus.NewBlockRewardCalculator(genesis.InitialReward, genesis.RewardHalvingInterval, genesis.MaxSupply),
-		DifficultyAdjuster: consensus.NewDifficultyAdjuster(genesis.DifficultyWindow, genesis.TargetBlockTime),
+		RewardCalculator:   genesis.NewRewardCalculator(),
+		DifficultyAdjuster: consensus.NewDifficultyAdjuster(genesis.DifficultyAlgorithm, genesis.DifficultyWindow, genesis.TargetBlockTime),
 		GenesisConfig:      genesis,
+		Params:             params,
+		TimeSource:         NewMedianTimeSource(systemClock{}, DefaultMaxTimeOffset),
 		BlockTimestamps:    make([]int64, 0),
+		quit:               make(chan struct{}),
+	}
+	bc.PendingTransactions.SetClock(bc.TimeSource)
+	bc.Orphans.SetClock(bc.TimeSource)
+	for range genesis.Deployments {
+		bc.deploymentCaches = append(bc.deploymentCaches, consensus.NewDeploymentCache())
 	}
 
 	// Check if genesis block exists in storage
 	genesisFromStore, err := store.GetBlock(0)
+	if err := bc.checkChainSpec(err == nil && genesisFromStore != nil); err != nil {
+		return nil, err
+	}
 	if err != nil || genesisFromStore == nil {
 		// Create and add genesis block
-		genesisBlock := CreateGenesisBlock(minerAddress)
+		genesisBlock := CreateGenesisBlock(genesis)
 		err := bc.AddBlock(genesisBlock)
 		if err != nil {
 			return nil, fmt.Errorf("failed to create genesis block: %v", err)
 		}
 	} else {
+		if genesis.GenesisHash != "" && genesisFromStore.BlockHash != genesis.GenesisHash {
+			return nil, fmt.Errorf("stored genesis %s does not match pinned genesis %s",
+				genesisFromStore.BlockHash, genesis.GenesisHash)
+		}
+
 		// Load blockchain from storage
 		bc.Blocks = append(bc.Blocks, genesisFromStore)
 		bc.BlockTimestamps = append(bc.BlockTimestamps, genesisFromStore.Timestamp)
+		for height := uint64(1); ; height++ {
+			block, err := store.GetBlock(height)
+			if err != nil || block == nil {
+				break
+			}
+			bc.Blocks = append(bc.Blocks, block)
+			bc.BlockTimestamps = append(bc.BlockTimestamps, block.Timestamp)
+		}
 		bc.rebuildUTXOSet()
 	}
 
+	// Restore fee history and unconfirmed transactions from the previous run
+	if err := bc.LoadFeeEstimates(); err != nil {
+		fmt.Printf("[FeeEstimator] Failed to restore fee estimates: %v\n", err)
+	}
+	if err := bc.LoadMempool(); err != nil {
+		fmt.Printf("[Mempool] Failed to restore mempool: %v\n", err)
+	}
+
+	bc.PendingTransactions.Start(context.Background())
+
+	bc.wg.Add(1)
+	go bc.persistMempoolLoop()
+
 	return bc, nil
 }
 
@@ -62,60 +120,50 @@ func NewBlockchain(store storage.Storage, minerAddress string) (*Blockchain, err
 func (bc *Blockchain) AddBlock(block *Block) error {
 	bc.mutex.Lock()
 	defer bc.mutex.Unlock()
+	return bc.addBlock(block)
+}
 
+// addBlock validates and connects a block; the caller must hold the write lock
+func (bc *Blockchain) addBlock(block *Block) error {
 	// Validate block
 	if err := bc.validateBlock(block); err != nil {
 		return fmt.Errorf("block validation failed: %v", err)
 	}
 
-	// Add transactions to UTXO set
-	for _, tx := range block.Transactions {
-		for _, output := range tx.Outputs {
-			utxo := &UTXO{
-				TxHash:    tx.TxHash,
-				OutIndex:  uint32(len(tx.Outputs)),
-				Value:     output.Value,
-				Address:   output.Address,
-				LockScript: output.LockScript,
-			}
-			bc.UTXOSet.AddUTXO(utxo)
-		}
-
-		// Remove spent outputs
-		for _, input := range tx.Inputs {
-			if input.TxHash != "" {
-				bc.UTXOSet.RemoveUTXO(input.TxHash, input.OutIndex)
-			}
-		}
-	}
+	// Apply transactions to UTXO set, keeping what they spent and the
+	// difficulty they were checked against for reorgs
+	undo := connectBlock(bc.UTXOSet, block)
+	undo.difficulty = bc.Difficulty
+	bc.undo = append(bc.undo, undo)
 
 	// Add to chain
 	bc.Blocks = append(bc.Blocks, block)
 	bc.BlockTimestamps = append(bc.BlockTimestamps, block.Timestamp)
 
-	// Update total mined
-	coinbaseReward := block.Transactions[0].Outputs[0].Value
-	bc.RewardCalculator.UpdateTotalMined(coinbaseReward)
-
 	// Store block
 	if err := bc.Chain.StoreBlock(block); err != nil {
 		return fmt.Errorf("failed to store block: %v", err)
 	}
 
-	// Remove transactions from mempool
-	for _, tx := range block.Transactions {
-		bc.PendingTransactions.RemoveTransaction(tx.TxHash)
+	// Record confirmation times before the mempool forgets the transactions
+	bc.FeeEstimator.ProcessBlock(block, bc.PendingTransactions.HasTransaction)
+
+	// Remove confirmed transactions and anything double-spending them from mempool
+	if evicted := bc.PendingTransactions.RemoveForBlock(block); evicted > 0 {
+		fmt.Printf("[Mempool] Evicted %d transactions conflicting with block #%d\n", evicted, block.Height)
 	}
 
-	// Adjust difficulty if needed
-	if bc.DifficultyAdjuster.ShouldAdjustDifficulty(block.Height) {
-		bc.Difficulty = bc.DifficultyAdjuster.AdjustDifficulty(
-			bc.Difficulty,
-			[]uint64{block.Height},
-			bc.BlockTimestamps,
-		)
+	// Drop transactions that can no longer be mined in the next block
+	if expired := bc.PendingTransactions.RemoveExpiredAt(block.Height + 1); expired > 0 {
+		fmt.Printf("[Mempool] Dropped %d transactions expiring at block #%d\n", expired, block.Height)
 	}
 
+	// Orphans whose parents were just confirmed can now enter the mempool
+	bc.processOrphans(block.Transactions...)
+
+	// Adjust difficulty if needed
+	bc.adjustDifficulty(block.Height, bc.BlockTimestamps)
+
 	fmt.Printf("[Blockchain] Block #%d added: %s, Txs: %d, UTXOs: %d\n",
 		block.Height, block.BlockHash[:16], len(block.Transactions), bc.UTXOSet.Count())
 
@@ -140,6 +188,19 @@ func (bc *Blockchain) validateBlock(block *Block) error {
 		if !block.IsGenesisBlock() {
 			return fmt.Errorf("first block must be genesis block")
 		}
+		if pinned := bc.GenesisConfig.GenesisHash; pinned != "" && block.BlockHash != pinned {
+			return fmt.Errorf("genesis hash %s does not match pinned %s", block.BlockHash, pinned)
+		}
+	}
+
+	// Timestamp must follow median-time-past and not run ahead of the network
+	if err := bc.checkBlockTime(block); err != nil {
+		return err
+	}
+
+	// Block hash must commit to the header
+	if block.BlockHash != block.CalculateHash() {
+		return fmt.Errorf("block hash does not match header")
 	}
 
 	// Check transactions
@@ -147,20 +208,87 @@ func (bc *Blockchain) validateBlock(block *Block) error {
 		return fmt.Errorf("block must have at least coinbase transaction")
 	}
 
+	// Block must fit the size and transaction count limits
+	if limit := bc.GenesisConfig.MaxTxPerBlock; limit > 0 && len(block.Transactions) > int(limit) {
+		return fmt.Errorf("block has %d transactions, limit is %d", len(block.Transactions), limit)
+	}
+	if limit := bc.GenesisConfig.MaxBlockSize; limit > 0 && block.Size() > int(limit) {
+		return fmt.Errorf("block is %d bytes, limit is %d", block.Size(), limit)
+	}
+
 	// First transaction must be coinbase
 	if !block.Transactions[0].IsCoinbase() {
 		return fmt.Errorf("first transaction must be coinbase")
 	}
 
-	// Validate each transaction
+	// Coinbase must commit to the block height, making its hash unique
+	if bc.GenesisConfig.Upgrades.IsActive(consensus.UpgradeCoinbaseHeight, block.Height) &&
+		block.Transactions[0].Inputs[0].CoinbaseHeight != block.Height {
+		return fmt.Errorf("coinbase commits to height %d, block is at %d",
+			block.Transactions[0].Inputs[0].CoinbaseHeight, block.Height)
+	}
+
+	// A transaction may not reuse the hash of one with unspent outputs,
+	// which would overwrite them in the UTXO set
+	seen := make(map[string]bool)
+	for _, tx := range block.Transactions {
+		if seen[tx.TxHash] || bc.hasUnspentOutputs(tx) {
+			return fmt.Errorf("duplicate transaction %s", tx.TxHash)
+		}
+		seen[tx.TxHash] = true
+	}
+
+	// Validate each transaction against a scratch view, applying them in order
+	// so later transactions can spend outputs created earlier in the block but
+	// no outpoint can be spent twice
+	view := newUTXOOverlay(bc.UTXOSet)
+	fees, coinbaseTotal := uint64(0), uint64(0)
 	for i, tx := range block.Transactions {
 		if i > 0 && tx.IsCoinbase() {
 			return fmt.Errorf("only first transaction can be coinbase")
 		}
 
-		if !tx.Validate(bc.UTXOSet) {
+		if i > 0 && !tx.HasAllInputs(view) {
+			return fmt.Errorf("transaction %s spends a missing or already spent output", tx.TxHash)
+		}
+
+		if !tx.Validate(view, bc.GenesisConfig.Upgrades, block.Height) {
 			return fmt.Errorf("transaction validation failed: %s", tx.TxHash)
 		}
+
+		if err := bc.checkContext(tx, view, block.Height, false); err != nil {
+			return err
+		}
+
+		outputTotal, ok := tx.checkedTotalOutput()
+		if !ok {
+			return fmt.Errorf("transaction %s output total overflows", tx.TxHash)
+		}
+		if i == 0 {
+			coinbaseTotal = outputTotal
+		} else {
+			fee := tx.GetTotalInput(view) - outputTotal
+			if fees+fee < fees {
+				return fmt.Errorf("block fees overflow at transaction %s", tx.TxHash)
+			}
+			fees += fee
+		}
+		connectTransaction(view, tx, block.Height)
+	}
+
+	// Coinbase may claim at most the block subsidy plus the fees of this block
+	maxCoinbase := bc.RewardCalculator.GetCoinbaseReward(block.Height, fees)
+	if block.Height == 0 {
+		maxCoinbase += bc.GenesisConfig.TotalAllocated()
+	}
+	if coinbaseTotal > maxCoinbase {
+		return fmt.Errorf("coinbase pays %d, exceeds subsidy plus fees %d", coinbaseTotal, maxCoinbase)
+	}
+
+	// Treasury share of the subsidy, if the chain has one
+	subsidy := bc.RewardCalculator.GetBlockReward(block.Height)
+	if err := bc.GenesisConfig.checkTreasuryOutputs(block.Transactions[0], block.Height, subsidy); err != nil {
+		return err
 	}
 
 	// Verify Merkle root
@@ -168,6 +296,12 @@ func (bc *Blockchain) validateBlock(block *Block) error {
 		return fmt.Errorf("invalid merkle root")
 	}
 
+	// Blocks must be mined at the difficulty the chain expects
+	if bc.GenesisConfig.Upgrades.IsActive(consensus.UpgradeDifficultyCheck, block.Height) &&
+		block.Difficulty != bc.Difficulty {
+		return fmt.Errorf("block difficulty %d, expected %d", block.Difficulty, bc.Difficulty)
+	}
+
 	// Verify PoW
 	pow := consensus.NewProofOfWork(block.Difficulty)
 	if !pow.Validate(block.BlockHash) {
@@ -177,6 +311,61 @@ func (bc *Blockchain) validateBlock(block *Block) error {
 	return nil
 }
 
+// checkContext runs the checks that depend on the block tx would go in:
+// expiry, lock times, vesting and coinbase maturity. Lock time rules apply to
+// blocks once their upgrades activate; with policy set, as for the mempool and
+// block templates, they always apply.
+func (bc *Blockchain) checkContext(tx *Transaction, view UTXOView, height uint64, policy bool) error {
+	upgrades := bc.GenesisConfig.Upgrades
+
+	if tx.IsExpired(height) {
+		return fmt.Errorf("transaction %s expired at height %d", tx.TxHash, tx.ExpiryHeight)
+	}
+
+	if policy || upgrades.IsActive(consensus.UpgradeLockTime, height) {
+		if err := bc.checkFinal(tx, height); err != nil {
+			return err
+		}
+	}
+
+	if policy || upgrades.IsActive(consensus.UpgradeRelativeLockTime, height) {
+		if err := tx.CheckSequenceLocks(view, height, bc.medianTimePast(height), bc.confirmedTime); err != nil {
+			return err
+		}
+	}
+
+	return bc.checkSpendable(tx, view, height)
+}
+
+// checkFinal verifies tx's lock time allows it in a block at height, comparing
+// time locks against the block's median-time-past
+func (bc *Blockchain) checkFinal(tx *Transaction, height uint64) error {
+	if !tx.IsFinal(height, bc.medianTimePast(height)) {
+		return fmt.Errorf("transaction %s is not final: locked until %d", tx.TxHash, tx.LockTime)
+	}
+	return nil
+}
+
+// checkSpendable verifies every input of tx may be spent in a block at height:
+// vesting locks have expired and coinbase outputs have matured
+func (bc *Blockchain) checkSpendable(tx *Transaction, view UTXOView, height uint64) error {
+	if err := tx.CheckUnlocked(view, height); err != nil {
+		return err
+	}
+	return tx.CheckCoinbaseMaturity(view, height, bc.GenesisConfig.CoinbaseMaturity)
+}
+
+// hasUnspentOutputs reports whether the UTXO set still holds an output of a
+// transaction with tx's hash
+func (bc *Blockchain) hasUnspentOutputs(tx *Transaction) bool {
+	for i := range tx.Outputs {
+		if bc.UTXOSet.FindUTXO(tx.TxHash, uint32(i)) != nil {
+			return true
+		}
+	}
+	return false
+}
+
 // GetLatestBlock returns the most recent block
 func (bc *Blockchain) GetLatestBlock() *Block {
 	bc.mutex.RLock()
@@ -219,9 +408,113 @@ func (bc *Blockchain) GetHeight() uint64 {
 	return uint64(len(bc.Blocks))
 }
 
-// AddPendingTransaction adds a transaction to mempool
-func (bc *Blockchain) AddPendingTransaction(tx *Transaction, fee uint64) error {
-	return bc.PendingTransactions.AddTransaction(tx, fee)
+// AddPendingTransaction adds a locally created transaction to mempool
+func (bc *Blockchain) AddPendingTransaction(tx *Transaction) error {
+	return bc.ProcessTransaction(tx, "")
+}
+
+// ProcessTransaction admits a transaction to the mempool. Relayed transactions
+// spending outputs we haven't seen yet are held in the orphan pool until their
+// parents arrive; local ones, with an empty peer, are rejected.
+func (bc *Blockchain) ProcessTransaction(tx *Transaction, peer string) error {
+	bc.mutex.RLock()
+	defer bc.mutex.RUnlock()
+
+	if bc.PendingTransactions.HasTransaction(tx.TxHash) || bc.Orphans.HasOrphan(tx.TxHash) {
+		return fmt.Errorf("transaction already known: %s", tx.TxHash)
+	}
+
+	if missing := bc.missingInputs(tx); len(missing) > 0 {
+		if peer == "" {
+			return fmt.Errorf("transaction %s spends unknown outputs: %v", tx.TxHash, missing)
+		}
+		if err := bc.Orphans.AddOrphan(tx, peer, missing); err != nil {
+			return err
+		}
+		fmt.Printf("[Mempool] Orphan tx %s waiting on %d inputs\n", tx.TxHash[:16], len(missing))
+		return nil
+	}
+
+	if err := bc.acceptToMempool(tx); err != nil {
+		return err
+	}
+
+	bc.processOrphans(tx)
+	return nil
+}
+
+// missingInputs returns the outpoints tx spends that neither the chain nor the mempool holds
+func (bc *Blockchain) missingInputs(tx *Transaction) []string {
+	if tx.IsCoinbase() {
+		return nil
+	}
+
+	var missing []string
+	for _, input := range tx.Inputs {
+		if bc.UTXOSet.FindUTXO(input.TxHash, input.OutIndex) == nil &&
+			bc.PendingTransactions.FindUTXO(input.TxHash, input.OutIndex) == nil {
+			missing = append(missing, outpointKey(input.TxHash, input.OutIndex))
+		}
+	}
+	return missing
+}
+
+// processOrphans moves orphans whose parents are now available into the mempool,
+// following chains of orphans recursively
+func (bc *Blockchain) processOrphans(parents ...*Transaction) {
+	queue := append([]*Transaction(nil), parents...)
+
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+
+		for _, orphan := range bc.Orphans.TakeChildren(parent) {
+			// Still waiting on another parent
+			if missing := bc.missingInputs(orphan.Tx); len(missing) > 0 {
+				bc.Orphans.AddOrphan(orphan.Tx, orphan.Peer, missing)
+				continue
+			}
+
+			if err := bc.acceptToMempool(orphan.Tx); err != nil {
+				continue
+			}
+
+			fmt.Printf("[Mempool] Orphan tx %s accepted\n", orphan.Tx.TxHash[:16])
+			queue = append(queue, orphan.Tx)
+		}
+	}
+}
+
+// acceptToMempool validates tx against the chain tip plus pending transactions
+// and adds it to the mempool with its actual fee
+func (bc *Blockchain) acceptToMempool(tx *Transaction) error {
+	if tx.IsCoinbase() {
+		return fmt.Errorf("coinbase transaction not allowed in mempool: %s", tx.TxHash)
+	}
+
+	view := bc.PendingTransactions.View(bc.UTXOSet)
+	if !tx.HasAllInputs(view) {
+		return fmt.Errorf("transaction has missing or spent inputs: %s", tx.TxHash)
+	}
+
+	// Must be valid and spendable in the next block
+	nextHeight := uint64(len(bc.Blocks))
+	if !tx.Validate(view, bc.GenesisConfig.Upgrades, nextHeight) {
+		return fmt.Errorf("transaction validation failed: %s", tx.TxHash)
+	}
+
+	// Lock times are enforced for relay even before their upgrades activate
+	if err := bc.checkContext(tx, view, nextHeight, true); err != nil {
+		return err
+	}
+
+	fee := tx.GetTotalInput(view) - tx.GetTotalOutput()
+	if err := bc.PendingTransactions.AddTransaction(tx, fee); err != nil {
+		return err
+	}
+
+	bc.FeeEstimator.TrackTransaction(tx.TxHash, float64(fee)/float64(tx.Size()), uint64(len(bc.Blocks)-1))
+	return nil
 }
 
 // GetPendingTransactions retrieves transactions for mining
@@ -243,31 +536,29 @@ func (bc *Blockchain) GetUnspentOutputs(address string) []*UTXO {
 	return bc.UTXOSet.FindUTXOsByAddress(address)
 }
 
-// rebuildUTXOSet reconstructs UTXO set from blocks
+// adjustDifficulty retargets after the block at height, given the
+// timestamps of the chain up to and including it
+func (bc *Blockchain) adjustDifficulty(height uint64, timestamps []int64) {
+	if bc.DifficultyAdjuster.ShouldAdjustDifficulty(height) {
+		bc.Difficulty = bc.DifficultyAdjuster.AdjustDifficulty(
+			bc.Difficulty,
+			[]uint64{height},
+			timestamps,
+		)
+	}
+}
+
+// rebuildUTXOSet reconstructs UTXO set, undo data and difficulty from blocks
 func (bc *Blockchain) rebuildUTXOSet() {
 	bc.UTXOSet = NewUTXOSet()
-
-	for _, block := range bc.Blocks {
-		for _, tx := range block.Transactions {
-			// Add outputs
-			for i, output := range tx.Outputs {
-				utxo := &UTXO{
-					TxHash:    tx.TxHash,
-					OutIndex:  uint32(i),
-					Value:     output.Value,
-					Address:   output.Address,
-					LockScript: output.LockScript,
-				}
-				bc.UTXOSet.AddUTXO(utxo)
-			}
-
-			// Remove spent inputs
-			for _, input := range tx.Inputs {
-				if input.TxHash != "" {
-					bc.UTXOSet.RemoveUTXO(input.TxHash, input.OutIndex)
-				}
-			}
-		}
+	bc.undo = make([]blockUndo, 0, len(bc.Blocks))
+	bc.Difficulty = bc.GenesisConfig.InitialDifficulty
+
+	for i, block := range bc.Blocks {
+		undo := connectBlock(bc.UTXOSet, block)
+		undo.difficulty = bc.Difficulty
+		bc.undo = append(bc.undo, undo)
+		bc.adjustDifficulty(block.Height, bc.BlockTimestamps[:i+1])
 	}
 }
 
@@ -277,7 +568,14 @@ func (bc *Blockchain) GetChainInfo() map[string]interface{} {
 	defer bc.mutex.RUnlock()
 
 	latestBlock := bc.Blocks[len(bc.Blocks)-1]
-	nextHalving := bc.RewardCalculator.GetBlocksUntilNextHalving(latestBlock.Height)
+	nextRewardChange := bc.RewardCalculator.GetBlocksUntilNextRewardChange(latestBlock.Height)
+
+	// Genesis allocations are part of the circulating supply
+	supplyMined := bc.RewardCalculator.GetSupplyAtHeight(latestBlock.Height) + bc.GenesisConfig.TotalAllocated()
+	minedPercentage := float64(0)
+	if bc.GenesisConfig.MaxSupply > 0 {
+		minedPercentage = float64(supplyMined) / float64(bc.GenesisConfig.MaxSupply) * 100
+	}
 
 	return map[string]interface{}{
 		"height":              bc.GetHeight(),
@@ -287,10 +585,11 @@ func (bc *Blockchain) GetChainInfo() map[string]interface{} {
 		"last_block_time":     latestBlock.Timestamp,
 		"total_transactions":  bc.countAllTransactions(),
 		"pending_txs":         bc.PendingTransactions.Size(),
+		"orphan_txs":          bc.Orphans.Size(),
 		"utxo_count":          bc.UTXOSet.Count(),
-		"supply_mined":        bc.RewardCalculator.TotalMinedCoins,
-		"mined_percentage":    bc.RewardCalculator.GetMinedPercentage(),
-		"blocks_until_halving": nextHalving,
+		"supply_mined":        supplyMined,
+		"mined_percentage":    minedPercentage,
+		"blocks_until_reward_change": nextRewardChange,
 		"current_reward":      bc.RewardCalculator.GetBlockReward(latestBlock.Height),
 	}
 }
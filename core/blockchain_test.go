@@ -0,0 +1,51 @@
+package core
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCoinbaseOutputOverflowRejected(t *testing.T) {
+	// Keep output checks inactive: the overflow check must not depend on them
+	bc := newTestChain(t, "output_checks=1000")
+
+	block, err := bc.NewBlockTemplate("miner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	coinbase := block.Transactions[0]
+	coinbase.Outputs = []Output{{Value: math.MaxUint64, Address: "miner"}, {Value: 2, Address: "miner"}}
+	coinbase.TxHash = coinbase.CalculateHash()
+
+	err = bc.AddBlock(solve(t, block))
+	if err == nil {
+		t.Fatal("block with overflowing coinbase accepted")
+	}
+	if bc.UTXOSet.GetBalance("miner") != 0 {
+		t.Fatal("overflowing coinbase reached the UTXO set")
+	}
+}
+
+func TestTransactionOutputOverflowRejected(t *testing.T) {
+	bc := newTestChain(t, "output_checks=1000")
+	blocks := generate(t, bc, 101, "miner")
+
+	// Outputs wrapping around to the input value would pass a plain sum
+	coinbase := blocks[0].Transactions[0]
+	value := coinbase.Outputs[0].Value
+	tx := spend(coinbase, []uint32{0}, "thief", math.MaxUint64, value+1)
+
+	if err := bc.AddPendingTransaction(tx); err == nil {
+		t.Fatal("transaction with overflowing outputs accepted")
+	}
+
+	block, err := bc.NewBlockTemplate("miner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Transactions = append(block.Transactions, tx)
+	if err := bc.AddBlock(solve(t, block)); err == nil || !strings.Contains(err.Error(), tx.TxHash) {
+		t.Fatalf("expected rejection of %s, got %v", tx.TxHash, err)
+	}
+}
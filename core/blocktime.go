@@ -0,0 +1,81 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+)
+
+// medianTimeSpan is how many previous blocks make up median-time-past
+const medianTimeSpan = 11
+
+// medianTimePast returns the median timestamp of the medianTimeSpan blocks
+// before height; the caller must hold the lock
+func (bc *Blockchain) medianTimePast(height uint64) int64 {
+	start := uint64(0)
+	if height > medianTimeSpan {
+		start = height - medianTimeSpan
+	}
+	if height == 0 || height > uint64(len(bc.BlockTimestamps)) {
+		return 0
+	}
+
+	times := append([]int64(nil), bc.BlockTimestamps[start:height]...)
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	return times[len(times)/2]
+}
+
+// confirmedTime returns the time from which relative time locks on outputs
+// created at height count: the block's median-time-past, or for genesis
+// outputs, which have no earlier blocks, the genesis timestamp. The caller
+// must hold the lock.
+func (bc *Blockchain) confirmedTime(height uint64) int64 {
+	if height == 0 {
+		return bc.GenesisConfig.Timestamp
+	}
+	return bc.medianTimePast(height)
+}
+
+// MedianTimePast returns the median-time-past of the next block. Time-based
+// timelocks compare against this rather than the miner-chosen block timestamp.
+func (bc *Blockchain) MedianTimePast() int64 {
+	bc.mutex.RLock()
+	defer bc.mutex.RUnlock()
+	return bc.medianTimePast(uint64(len(bc.Blocks)))
+}
+
+// adjustedTime returns the current network-adjusted time in unix seconds
+func (bc *Blockchain) adjustedTime() int64 {
+	return bc.TimeSource.Now().Unix()
+}
+
+// SetTimeSource replaces the network time used for block validation, mining
+// and mempool and orphan expiry
+func (bc *Blockchain) SetTimeSource(ts TimeSource) {
+	bc.mutex.Lock()
+	defer bc.mutex.Unlock()
+
+	bc.TimeSource = ts
+	bc.PendingTransactions.SetClock(ts)
+	bc.Orphans.SetClock(ts)
+}
+
+// checkBlockTime requires a block to be later than median-time-past and no
+// further ahead of adjusted time than the allowed drift
+func (bc *Blockchain) checkBlockTime(block *Block) error {
+	// The genesis timestamp is fixed by the chain params
+	if block.Height == 0 {
+		return nil
+	}
+
+	if mtp := bc.medianTimePast(block.Height); block.Timestamp <= mtp {
+		return fmt.Errorf("block timestamp %d is not after median time past %d", block.Timestamp, mtp)
+	}
+
+	if drift := bc.GenesisConfig.MaxFutureDrift; drift > 0 {
+		if limit := bc.adjustedTime() + drift; block.Timestamp > limit {
+			return fmt.Errorf("block timestamp %d is more than %ds ahead of network time", block.Timestamp, drift)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// genesisAllocation finds the genesis output paying address
+func genesisAllocation(t *testing.T, bc *Blockchain, address string) (*Transaction, uint32) {
+	t.Helper()
+
+	for _, tx := range bc.Blocks[0].Transactions {
+		for i, output := range tx.Outputs {
+			if output.Address == address {
+				return tx, uint32(i)
+			}
+		}
+	}
+	t.Fatalf("no genesis allocation to %s", address)
+	return nil, 0
+}
+
+func TestRelativeTimeLockOnGenesisOutput(t *testing.T) {
+	params := RegtestParams()
+	params.Genesis.Timestamp = time.Now().Add(-24 * time.Hour).Unix()
+	params.Genesis.Allocations = []GenesisAllocation{{Address: "alice", Amount: 1000000}}
+	bc, err := NewBlockchain(newMemStorage(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Close()
+	generate(t, bc, medianTimeSpan, "miner")
+
+	allocation, outIndex := genesisAllocation(t, bc, "alice")
+	lockedSpend := func(units uint32, to string) *Transaction {
+		tx := spend(allocation, []uint32{outIndex}, to, 990000)
+		tx.Version = 2
+		tx.Inputs[0].Sequence = SequenceLockTimeTypeFlag | units
+		tx.TxHash = tx.CalculateHash()
+		return tx
+	}
+
+	// About six days from genesis, which was a day ago: not yet unlocked
+	err = bc.AddPendingTransaction(lockedSpend(1000, "bob"))
+	if err == nil || !strings.Contains(err.Error(), "time locked") {
+		t.Fatalf("relative time lock on a genesis output not enforced: %v", err)
+	}
+
+	// About an hour from genesis has passed
+	if err := bc.AddPendingTransaction(lockedSpend(7, "carol")); err != nil {
+		t.Fatal(err)
+	}
+}
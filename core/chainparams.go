@@ -0,0 +1,121 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/consensus"
+)
+
+// Network names
+const (
+	NETWORK_MAINNET = "mainnet"
+	NETWORK_TESTNET = "testnet"
+	NETWORK_REGTEST = "regtest"
+	NETWORK_DEVNET  = "devnet"
+)
+
+// ChainParams holds everything that differs between networks
+type ChainParams struct {
+	Name           string
+	Genesis        *GenesisConfig
+	AddressVersion byte   // Version byte encoded in addresses
+	P2PMagic       uint32 // Prefix identifying network messages
+	DefaultP2PPort int
+	DefaultRPCPort int
+	DataDir        string // Default data directory
+	SpecHash       string `json:"-"` // Hash of the chain spec file ("" = built-in network)
+}
+
+// chainParamsRegistry maps network names to their params constructors
+var chainParamsRegistry = map[string]func() *ChainParams{
+	NETWORK_MAINNET: MainnetParams,
+	NETWORK_TESTNET: TestnetParams,
+	NETWORK_REGTEST: RegtestParams,
+	NETWORK_DEVNET:  DevnetParams,
+}
+
+// GetChainParams returns a fresh copy of the params for a named network
+func GetChainParams(network string) (*ChainParams, error) {
+	constructor, exists := chainParamsRegistry[network]
+	if !exists {
+		return nil, fmt.Errorf("unknown network %q, expected one of %v", network, Networks())
+	}
+	return constructor(), nil
+}
+
+// OverrideUpgrades changes upgrade activation heights. Only regtest allows it,
+// so tests can exercise the rules on either side of an activation height.
+func (cp *ChainParams) OverrideUpgrades(overrides consensus.UpgradeSchedule) error {
+	if cp.Name != NETWORK_REGTEST {
+		return fmt.Errorf("upgrade heights can only be overridden on %s", NETWORK_REGTEST)
+	}
+	if err := overrides.Validate(); err != nil {
+		return err
+	}
+	cp.Genesis.Upgrades = cp.Genesis.Upgrades.Override(overrides)
+	return nil
+}
+
+// Networks lists the registered network names
+func Networks() []string {
+	names := make([]string, 0, len(chainParamsRegistry))
+	for name := range chainParamsRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// MainnetParams returns the production network params
+func MainnetParams() *ChainParams {
+	return &ChainParams{
+		Name:           NETWORK_MAINNET,
+		Genesis:        GetMainnetGenesis(),
+		AddressVersion: 0x01,
+		P2PMagic:       0x56445831, // "VDX1"
+		DefaultP2PPort: 7333,
+		DefaultRPCPort: 7332,
+		DataDir:        "./blockchain_data",
+	}
+}
+
+// TestnetParams returns the public test network params
+func TestnetParams() *ChainParams {
+	return &ChainParams{
+		Name:           NETWORK_TESTNET,
+		Genesis:        GetTestnetGenesis(),
+		AddressVersion: 0x02,
+		P2PMagic:       0x56445854, // "VDXT"
+		DefaultP2PPort: 17333,
+		DefaultRPCPort: 17332,
+		DataDir:        "./blockchain_data_testnet",
+	}
+}
+
+// RegtestParams returns params for local integration tests, with trivial
+// difficulty so GenerateBlocks produces blocks instantly
+func RegtestParams() *ChainParams {
+	return &ChainParams{
+		Name:           NETWORK_REGTEST,
+		Genesis:        GetRegtestGenesis(),
+		AddressVersion: 0x03,
+		P2PMagic:       0x56445852, // "VDXR"
+		DefaultP2PPort: 27333,
+		DefaultRPCPort: 27332,
+		DataDir:        "./blockchain_data_regtest",
+	}
+}
+
+// DevnetParams returns params for short-lived developer networks
+func DevnetParams() *ChainParams {
+	return &ChainParams{
+		Name:           NETWORK_DEVNET,
+		Genesis:        GetDevnetGenesis(),
+		AddressVersion: 0x04,
+		P2PMagic:       0x56445844, // "VDXD"
+		DefaultP2PPort: 37333,
+		DefaultRPCPort: 37332,
+		DataDir:        "./blockchain_data_devnet",
+	}
+}
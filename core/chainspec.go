@@ -0,0 +1,166 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// chainSpecStateKey is the storage key holding the hash of the chain spec a
+	// data directory was created from
+	chainSpecStateKey = "chainspec_hash"
+
+	// chainSpecHashVersion prefixes the hashed canonical form, so the form can
+	// change without old and new hashes colliding
+	chainSpecHashVersion = "chainspec-v1"
+)
+
+// specLocalSettings are top-level spec fields that only affect the local node
+// and so are left out of the spec hash
+var specLocalSettings = []string{"DataDir", "DefaultP2PPort", "DefaultRPCPort"}
+
+// LoadChainSpec loads the params of a custom network from a spec file. The
+// spec is a ChainParams document with the full GenesisConfig under "Genesis".
+// Name defaults to the chain ID and DataDir to a directory named after it.
+func LoadChainSpec(path string) (*ChainParams, error) {
+	var params ChainParams
+	data, err := readSpecFile(path, &params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load chain spec: %v", err)
+	}
+
+	if params.Genesis == nil {
+		return nil, fmt.Errorf("chain spec has no genesis config")
+	}
+	if params.Name == "" {
+		params.Name = params.Genesis.ChainID
+	}
+	if params.DataDir == "" {
+		params.DataDir = "./blockchain_data_" + params.Name
+	}
+
+	if err := params.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid chain spec: %v", err)
+	}
+
+	hash, err := computeSpecHash(data)
+	if err != nil {
+		return nil, err
+	}
+	params.SpecHash = hash
+
+	return &params, nil
+}
+
+// Validate checks the network params and their genesis config
+func (cp *ChainParams) Validate() error {
+	if cp.Name == "" {
+		return fmt.Errorf("network name is required")
+	}
+	if cp.Genesis == nil {
+		return fmt.Errorf("genesis config is required")
+	}
+	if cp.DefaultP2PPort < 0 || cp.DefaultP2PPort > 65535 {
+		return fmt.Errorf("invalid p2p port %d", cp.DefaultP2PPort)
+	}
+	if cp.DefaultRPCPort < 0 || cp.DefaultRPCPort > 65535 {
+		return fmt.Errorf("invalid rpc port %d", cp.DefaultRPCPort)
+	}
+	return cp.Genesis.Validate()
+}
+
+// computeSpecHash hashes the spec file content, so the hash doesn't change
+// when GenesisConfig gains or loses fields. The JSON is canonicalized first:
+// key order and formatting don't matter, and local settings are dropped.
+func computeSpecHash(data []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var spec map[string]interface{}
+	if err := decoder.Decode(&spec); err != nil {
+		return "", fmt.Errorf("failed to decode chain spec: %v", err)
+	}
+
+	// Field names match case-insensitively when decoding, so here too
+	for key := range spec {
+		for _, local := range specLocalSettings {
+			if strings.EqualFold(key, local) {
+				delete(spec, key)
+			}
+		}
+	}
+
+	canonical, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode chain spec: %v", err)
+	}
+
+	hash := sha256.Sum256(append([]byte(chainSpecHashVersion+"\n"), canonical...))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// readSpecFile decodes a JSON spec file into v, rejecting unknown fields so a
+// misspelled parameter doesn't silently fall back to its zero value. It
+// returns the raw file content.
+func readSpecFile(path string, v interface{}) ([]byte, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", "":
+	case ".toml":
+		return nil, fmt.Errorf("TOML specs are not supported in this build, convert %s to JSON", path)
+	default:
+		return nil, fmt.Errorf("unsupported spec format %q", filepath.Ext(path))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+
+	return data, nil
+}
+
+// checkChainSpec records the spec hash in a new data directory, and refuses to
+// open an existing one that was created from a different spec
+func (bc *Blockchain) checkChainSpec(existing bool) error {
+	stored, err := bc.Chain.GetState(chainSpecStateKey)
+	if err != nil {
+		stored = nil
+	}
+
+	if len(stored) == 0 {
+		if existing {
+			if bc.Params.SpecHash != "" {
+				return fmt.Errorf("data directory belongs to a built-in network, not chain spec %s", bc.Params.SpecHash)
+			}
+			return nil
+		}
+		if bc.Params.SpecHash == "" {
+			return nil
+		}
+		if err := bc.Chain.StoreState(chainSpecStateKey, []byte(bc.Params.SpecHash)); err != nil {
+			return fmt.Errorf("failed to record chain spec hash: %v", err)
+		}
+		return nil
+	}
+
+	if string(stored) != bc.Params.SpecHash {
+		if bc.Params.SpecHash == "" {
+			return fmt.Errorf("data directory was created from chain spec %s, not network %s", stored, bc.Params.Name)
+		}
+		return fmt.Errorf("data directory was created from chain spec %s, got %s", stored, bc.Params.SpecHash)
+	}
+
+	return nil
+}
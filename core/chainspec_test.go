@@ -0,0 +1,133 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSpec writes data to a spec file in a fresh directory and loads it
+func writeSpec(t *testing.T, data string) (*ChainParams, string) {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	params, err := LoadChainSpec(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return params, path
+}
+
+func testSpec(t *testing.T) map[string]interface{} {
+	t.Helper()
+
+	params := RegtestParams()
+	params.Name = "private"
+	params.Genesis.ChainID = "private-1"
+	data, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Decode numbers as written, as heights may not fit a float64
+	var spec map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&spec); err != nil {
+		t.Fatal(err)
+	}
+	return spec
+}
+
+func encodeSpec(t *testing.T, spec map[string]interface{}, indent bool) string {
+	t.Helper()
+
+	var data []byte
+	var err error
+	if indent {
+		data, err = json.MarshalIndent(spec, "", "    ")
+	} else {
+		data, err = json.Marshal(spec)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestChainSpecHashFollowsContent(t *testing.T) {
+	spec := testSpec(t)
+	params, _ := writeSpec(t, encodeSpec(t, spec, false))
+	if params.SpecHash == "" {
+		t.Fatal("spec has no hash")
+	}
+
+	// Formatting and local settings don't change the network
+	if other, _ := writeSpec(t, encodeSpec(t, spec, true)); other.SpecHash != params.SpecHash {
+		t.Fatal("reformatting changed the spec hash")
+	}
+	spec["DataDir"] = "/elsewhere"
+	spec["DefaultP2PPort"] = 1234
+	if other, _ := writeSpec(t, encodeSpec(t, spec, false)); other.SpecHash != params.SpecHash {
+		t.Fatal("local settings changed the spec hash")
+	}
+
+	// A spec leaving out fields hashes what it says, not the decoded struct
+	genesis := spec["Genesis"].(map[string]interface{})
+	delete(genesis, "Deployments")
+	withoutDeployments, _ := writeSpec(t, encodeSpec(t, spec, false))
+	if withoutDeployments.SpecHash == params.SpecHash {
+		t.Fatal("removing deployments kept the spec hash")
+	}
+	if again, _ := writeSpec(t, encodeSpec(t, spec, false)); again.SpecHash != withoutDeployments.SpecHash {
+		t.Fatal("spec hash is not stable")
+	}
+
+	genesis["MaxTxPerBlock"] = 5
+	if other, _ := writeSpec(t, encodeSpec(t, spec, false)); other.SpecHash == withoutDeployments.SpecHash {
+		t.Fatal("consensus change kept the spec hash")
+	}
+}
+
+func TestChainSpecPinsDataDirectory(t *testing.T) {
+	spec := testSpec(t)
+	params, _ := writeSpec(t, encodeSpec(t, spec, false))
+
+	store := newMemStorage()
+	bc, err := NewBlockchain(store, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc.Close()
+
+	reopened, err := NewBlockchain(store, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reopened.Close()
+
+	spec["Genesis"].(map[string]interface{})["MaxTxPerBlock"] = 5
+	changed, _ := writeSpec(t, encodeSpec(t, spec, false))
+	if _, err := NewBlockchain(store, changed); err == nil || !strings.Contains(err.Error(), "chain spec") {
+		t.Fatalf("changed spec opened the data directory: %v", err)
+	}
+	if _, err := NewBlockchain(store, RegtestParams()); err == nil {
+		t.Fatal("built-in network opened a chain spec data directory")
+	}
+}
+
+func TestChainSpecRejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(path, []byte(`{"Genesis":{"ChainID":"x","Bogus":1}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadChainSpec(path); err == nil {
+		t.Fatal("spec with an unknown field loaded")
+	}
+}
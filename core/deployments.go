@@ -0,0 +1,98 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/consensus"
+)
+
+// DeploymentInfo reports a version bits deployment as seen by the next block
+type DeploymentInfo struct {
+	Name          string
+	Bit           uint8
+	StartHeight   uint64
+	TimeoutHeight uint64
+	Threshold     uint32
+	Window        uint32
+	State         consensus.DeploymentState
+	Signals       uint32 // Signaling blocks so far in the current window
+}
+
+// validateDeployments checks each deployment and that no two deployments
+// signal with the same bit while both are collecting signals
+func (gc *GenesisConfig) validateDeployments() error {
+	names := make(map[string]bool)
+	for i, deployment := range gc.Deployments {
+		if err := deployment.Validate(gc.DifficultyWindow); err != nil {
+			return err
+		}
+		if names[deployment.Name] {
+			return fmt.Errorf("duplicate deployment %s", deployment.Name)
+		}
+		names[deployment.Name] = true
+
+		for _, other := range gc.Deployments[:i] {
+			if other.Bit == deployment.Bit &&
+				other.StartHeight < deployment.TimeoutHeight && deployment.StartHeight < other.TimeoutHeight {
+				return fmt.Errorf("deployments %s and %s overlap on bit %d", other.Name, deployment.Name, deployment.Bit)
+			}
+		}
+	}
+	return nil
+}
+
+// GetDeployments reports the state of every deployment for the next block
+func (bc *Blockchain) GetDeployments() []DeploymentInfo {
+	bc.mutex.RLock()
+	defer bc.mutex.RUnlock()
+
+	height := uint64(len(bc.Blocks))
+	window := bc.GenesisConfig.DifficultyWindow
+
+	infos := make([]DeploymentInfo, 0, len(bc.GenesisConfig.Deployments))
+	for i, deployment := range bc.GenesisConfig.Deployments {
+		infos = append(infos, DeploymentInfo{
+			Name:          deployment.Name,
+			Bit:           deployment.Bit,
+			StartHeight:   deployment.StartHeight,
+			TimeoutHeight: deployment.TimeoutHeight,
+			Threshold:     deployment.Threshold,
+			Window:        window,
+			State:         deployment.StateAt(height, window, bc.blockVersion, bc.deploymentCaches[i]),
+			Signals:       deployment.SignalCount(height, window, bc.blockVersion),
+		})
+	}
+	return infos
+}
+
+// IsDeploymentActive reports whether the named deployment is active for a block
+// at height; the caller must hold the lock
+func (bc *Blockchain) IsDeploymentActive(name string, height uint64) bool {
+	for i, deployment := range bc.GenesisConfig.Deployments {
+		if deployment.Name == name {
+			state := deployment.StateAt(height, bc.GenesisConfig.DifficultyWindow, bc.blockVersion, bc.deploymentCaches[i])
+			return state == consensus.DeploymentActive
+		}
+	}
+	return false
+}
+
+// forgetDeploymentStates drops cached deployment states that depend on the
+// block at height, when it is disconnected
+func (bc *Blockchain) forgetDeploymentStates(height uint64) {
+	for _, cache := range bc.deploymentCaches {
+		cache.Forget(height)
+	}
+}
+
+// blockVersion returns the version of the connected block at height
+func (bc *Blockchain) blockVersion(height uint64) uint32 {
+	return bc.Blocks[height].Version
+}
+
+// nextBlockVersion returns the version a new block at height should carry,
+// signaling every deployment that is still collecting signals
+func (bc *Blockchain) nextBlockVersion(height uint64) uint32 {
+	return consensus.ComputeBlockVersion(bc.GenesisConfig.Deployments, bc.deploymentCaches, height,
+		bc.GenesisConfig.DifficultyWindow, bc.blockVersion)
+}
@@ -0,0 +1,238 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+const (
+	// feeEstimatesStateKey is the storage state key holding fee estimator data
+	feeEstimatesStateKey = "fee_estimates"
+
+	minBucketFeeRate = 1.0   // satoshis per byte
+	maxBucketFeeRate = 1e5   // satoshis per byte
+	feeBucketSpacing = 1.1   // ratio between neighbouring buckets
+	maxConfirmTarget = 48    // blocks
+	feeStatsDecay    = 0.998 // per-block decay so old data fades out
+	minBucketSamples = 1.0   // decayed samples needed before trusting a bucket range
+)
+
+// feeBucket tracks confirmation history for one fee-rate range
+type feeBucket struct {
+	MinFeeRate float64   // Lower bound, satoshis per byte
+	Confirmed  []float64 // Confirmed[i]: txs confirmed within i+1 blocks
+	Total      float64   // All txs that confirmed or gave up waiting
+}
+
+// trackedTx is a mempool transaction the estimator is waiting on
+type trackedTx struct {
+	Height uint64 // Tip height when it entered the mempool
+	Bucket int
+}
+
+// FeeEstimator estimates fee rates from observed confirmation times
+type FeeEstimator struct {
+	mutex   sync.Mutex
+	buckets []*feeBucket
+	tracked map[string]trackedTx
+}
+
+// NewFeeEstimator creates an estimator with exponentially spaced fee-rate buckets
+func NewFeeEstimator() *FeeEstimator {
+	fe := &FeeEstimator{
+		tracked: make(map[string]trackedTx),
+	}
+
+	for rate := minBucketFeeRate; rate <= maxBucketFeeRate; rate *= feeBucketSpacing {
+		fe.buckets = append(fe.buckets, &feeBucket{
+			MinFeeRate: rate,
+			Confirmed:  make([]float64, maxConfirmTarget),
+		})
+	}
+
+	return fe
+}
+
+// TrackTransaction records a transaction entering the mempool at the given tip height
+func (fe *FeeEstimator) TrackTransaction(txHash string, feeRate float64, height uint64) {
+	fe.mutex.Lock()
+	defer fe.mutex.Unlock()
+
+	fe.tracked[txHash] = trackedTx{
+		Height: height,
+		Bucket: fe.bucketIndex(feeRate),
+	}
+}
+
+// ProcessBlock records confirmations for the transactions in a newly connected block.
+// inMempool reports whether a tracked transaction is still pending.
+func (fe *FeeEstimator) ProcessBlock(block *Block, inMempool func(txHash string) bool) {
+	fe.mutex.Lock()
+	defer fe.mutex.Unlock()
+
+	// Fade out old observations
+	for _, bucket := range fe.buckets {
+		for i := range bucket.Confirmed {
+			bucket.Confirmed[i] *= feeStatsDecay
+		}
+		bucket.Total *= feeStatsDecay
+	}
+
+	for _, tx := range block.Transactions {
+		entry, exists := fe.tracked[tx.TxHash]
+		if !exists {
+			continue
+		}
+		delete(fe.tracked, tx.TxHash)
+
+		if block.Height <= entry.Height {
+			continue
+		}
+
+		blocks := int(block.Height - entry.Height)
+		bucket := fe.buckets[entry.Bucket]
+		for target := blocks; target <= maxConfirmTarget; target++ {
+			bucket.Confirmed[target-1]++
+		}
+		bucket.Total++
+	}
+
+	for txHash, entry := range fe.tracked {
+		switch {
+		case !inMempool(txHash):
+			// Evicted or expired without confirming; says nothing about the fee
+			delete(fe.tracked, txHash)
+		case block.Height > entry.Height && block.Height-entry.Height > maxConfirmTarget:
+			// Waited longer than any target we answer for. After a reorg the
+			// entry height can be above the new tip, which is no wait at all.
+			fe.buckets[entry.Bucket].Total++
+			delete(fe.tracked, txHash)
+		}
+	}
+}
+
+// EstimateFee returns the lowest fee rate (satoshis per byte) that confirmed within
+// targetBlocks with at least the given confidence (0-1)
+func (fe *FeeEstimator) EstimateFee(targetBlocks int, confidence float64) (float64, error) {
+	if targetBlocks < 1 || targetBlocks > maxConfirmTarget {
+		return 0, fmt.Errorf("confirmation target must be between 1 and %d blocks", maxConfirmTarget)
+	}
+	if confidence <= 0 || confidence > 1 {
+		return 0, fmt.Errorf("confidence must be in (0, 1]")
+	}
+
+	fe.mutex.Lock()
+	defer fe.mutex.Unlock()
+
+	// Walk down from the highest fee rate, grouping buckets until each range
+	// has enough samples, and stop at the first range that misses the target
+	best := -1
+	confirmed, total := 0.0, 0.0
+	for i := len(fe.buckets) - 1; i >= 0; i-- {
+		confirmed += fe.buckets[i].Confirmed[targetBlocks-1]
+		total += fe.buckets[i].Total
+
+		if total < minBucketSamples {
+			continue
+		}
+		if confirmed/total < confidence {
+			break
+		}
+
+		best = i
+		confirmed, total = 0, 0
+	}
+
+	if best < 0 {
+		return 0, fmt.Errorf("insufficient data to estimate fee for %d blocks", targetBlocks)
+	}
+
+	return fe.buckets[best].MinFeeRate, nil
+}
+
+// bucketIndex finds the bucket a fee rate belongs to
+func (fe *FeeEstimator) bucketIndex(feeRate float64) int {
+	for i := len(fe.buckets) - 1; i > 0; i-- {
+		if feeRate >= fe.buckets[i].MinFeeRate {
+			return i
+		}
+	}
+	return 0
+}
+
+// feeEstimatorState is the persisted form of the estimator
+type feeEstimatorState struct {
+	Buckets []*feeBucket
+	Tracked map[string]trackedTx
+}
+
+// MarshalJSON encodes the estimator state
+func (fe *FeeEstimator) MarshalJSON() ([]byte, error) {
+	fe.mutex.Lock()
+	defer fe.mutex.Unlock()
+	return json.Marshal(feeEstimatorState{Buckets: fe.buckets, Tracked: fe.tracked})
+}
+
+// UnmarshalJSON restores estimator state saved with the same bucket layout
+func (fe *FeeEstimator) UnmarshalJSON(data []byte) error {
+	var state feeEstimatorState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	fe.mutex.Lock()
+	defer fe.mutex.Unlock()
+
+	if len(state.Buckets) != len(fe.buckets) {
+		return fmt.Errorf("fee bucket layout mismatch: expected %d, got %d", len(fe.buckets), len(state.Buckets))
+	}
+	for _, bucket := range state.Buckets {
+		if bucket == nil || len(bucket.Confirmed) != maxConfirmTarget {
+			return fmt.Errorf("invalid fee bucket data")
+		}
+	}
+
+	fe.buckets = state.Buckets
+	fe.tracked = state.Tracked
+	if fe.tracked == nil {
+		fe.tracked = make(map[string]trackedTx)
+	}
+
+	return nil
+}
+
+// SaveFeeEstimates writes fee estimator state to storage
+func (bc *Blockchain) SaveFeeEstimates() error {
+	data, err := json.Marshal(bc.FeeEstimator)
+	if err != nil {
+		return fmt.Errorf("failed to encode fee estimates: %v", err)
+	}
+
+	if err := bc.Chain.StoreState(feeEstimatesStateKey, data); err != nil {
+		return fmt.Errorf("failed to store fee estimates: %v", err)
+	}
+
+	return nil
+}
+
+// LoadFeeEstimates restores fee estimator state from storage
+func (bc *Blockchain) LoadFeeEstimates() error {
+	data, err := bc.Chain.GetState(feeEstimatesStateKey)
+	if err != nil || len(data) == 0 {
+		// Nothing persisted yet
+		return nil
+	}
+
+	if err := json.Unmarshal(data, bc.FeeEstimator); err != nil {
+		return fmt.Errorf("failed to decode fee estimates: %v", err)
+	}
+
+	return nil
+}
+
+// EstimateFee returns the fee rate (satoshis per byte) needed to confirm within
+// targetBlocks with the given confidence (0-1)
+func (bc *Blockchain) EstimateFee(targetBlocks int, confidence float64) (float64, error) {
+	return bc.FeeEstimator.EstimateFee(targetBlocks, confidence)
+}
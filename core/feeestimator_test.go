@@ -0,0 +1,59 @@
+package core
+
+import (
+	"testing"
+)
+
+// bucketTotal sums the confirmed-or-failed counts of every bucket
+func bucketTotal(fe *FeeEstimator) float64 {
+	total := 0.0
+	for _, bucket := range fe.buckets {
+		total += bucket.Total
+	}
+	return total
+}
+
+func TestFeeEstimateFromConfirmations(t *testing.T) {
+	fe := NewFeeEstimator()
+	inMempool := func(string) bool { return true }
+
+	for height := uint64(1); height < 30; height++ {
+		tx := pendingTx("funding", uint32(height), height)
+		fe.TrackTransaction(tx.TxHash, 50, height-1)
+		fe.ProcessBlock(&Block{Height: height, Transactions: []*Transaction{tx}}, inMempool)
+	}
+
+	rate, err := fe.EstimateFee(2, 0.9)
+	if err != nil || rate < 40 || rate > 55 {
+		t.Fatalf("estimate %v, %v", rate, err)
+	}
+
+	data, err := fe.MarshalJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	restored := NewFeeEstimator()
+	if err := restored.UnmarshalJSON(data); err != nil {
+		t.Fatal(err)
+	}
+	if restoredRate, err := restored.EstimateFee(2, 0.9); err != nil || restoredRate != rate {
+		t.Fatalf("restored estimate %v, %v", restoredRate, err)
+	}
+}
+
+func TestFeeEstimatorAfterDeepReorg(t *testing.T) {
+	fe := NewFeeEstimator()
+	inMempool := func(string) bool { return true }
+
+	// Entered the mempool at height 10; a reorg then connects a block at 8
+	tx := pendingTx("funding", 0, 5)
+	fe.TrackTransaction(tx.TxHash, 50, 10)
+	fe.ProcessBlock(&Block{Height: 8}, inMempool)
+
+	if total := bucketTotal(fe); total != 0 {
+		t.Fatalf("transaction counted as failed after reorg: total %v", total)
+	}
+	if _, tracked := fe.tracked[tx.TxHash]; !tracked {
+		t.Fatal("transaction no longer tracked")
+	}
+}
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"fmt"
 	"time"
+
+	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/consensus"
 )
 
 // Genesis configuration for VOIDEX mainnet
@@ -9,29 +12,69 @@ const (
 	MAINNET_CHAIN_ID           = "voidex-mainnet"
 	MAINNET_VERSION            = uint32(1)
 	MAINNET_BLOCK_TIME         = 60 // seconds
-	MAINNET_INITIAL_SUPPLY     = uint64(50000000 * 100000000) // 50M coins in satoshis
+	MAINNET_MAX_SUPPLY         = uint64(50000000 * 100000000) // 50M coins in satoshis
 	MAINNET_INITIAL_REWARD     = uint64(50 * 100000000) // 50 coins per block
 	MAINNET_REWARD_HALVING     = uint64(210000) // Halve reward every 210k blocks
 	MAINNET_MAX_BLOCK_SIZE     = uint32(4000000) // 4MB
 	MAINNET_MAX_TX_PER_BLOCK   = uint32(2000)
 	MAINNET_DIFFICULTY_WINDOW  = uint32(2016) // Adjust every 2 weeks
 	MAINNET_TARGET_BLOCK_TIME  = uint32(60) // seconds
+	MAINNET_MAX_FUTURE_DRIFT   = int64(2 * 60 * 60) // seconds
+	MAINNET_COINBASE_MATURITY  = uint64(100) // blocks before a coinbase can be spent
+
+	// Activation heights of scheduled consensus upgrades
+	MAINNET_OUTPUT_CHECKS_HEIGHT     = uint64(100000)
+	MAINNET_DIFFICULTY_CHECK_HEIGHT  = uint64(100000)
+	MAINNET_COINBASE_HEIGHT_HEIGHT   = uint64(100000)
+	MAINNET_LOCKTIME_HEIGHT          = uint64(100000)
+	MAINNET_RELATIVE_LOCKTIME_HEIGHT = uint64(100000)
+	TESTNET_OUTPUT_CHECKS_HEIGHT     = uint64(1000)
+	TESTNET_DIFFICULTY_CHECK_HEIGHT  = uint64(1000)
+	TESTNET_COINBASE_HEIGHT_HEIGHT   = uint64(1000)
+	TESTNET_LOCKTIME_HEIGHT          = uint64(1000)
+	TESTNET_RELATIVE_LOCKTIME_HEIGHT = uint64(1000)
+
+	// Unspendable burn address (all-zero hash160) receiving the genesis subsidy
+	MAINNET_GENESIS_ADDRESS    = "VDXQLbz7JHiBTspS962RLKV8GndWFwjA5K66"
+	MAINNET_GENESIS_HASH       = "91e7b5c5e5e26df1f94140cda313c7c31ff9a32882c493e8cfd1f1153d4e2f1c"
+
+	// Burn addresses for the other networks, encoded with their version bytes
+	TESTNET_GENESIS_ADDRESS    = "VDXogCyDbaRMvkdsHB3qfdyFYaG1WtWyNoVK"
+	TESTNET_GENESIS_HASH       = "941887a25b3061807c833c7a84aa878a92dc51dece22361f9c6701d222c0deb1"
+	REGTEST_GENESIS_ADDRESS    = "VDX2D1oxKts8YPdTJRG5FzxTNpMtWmqBnjrht"
+	DEVNET_GENESIS_ADDRESS     = "VDX2cMQwSC9qirWGjZM6gLGwW69X22mx9jRA9"
 )
 
+// GenesisAllocation pays coins directly in the genesis block (premine / vesting)
+type GenesisAllocation struct {
+	Address      string
+	Amount       uint64
+	UnlockHeight uint64 // Coins can't be spent before this height (0 = immediately)
+}
+
 // GenesisConfig holds genesis block parameters
 type GenesisConfig struct {
 	ChainID              string
 	Version              uint32
 	Timestamp            int64
 	InitialDifficulty    uint32
-	InitialReward        uint64
+	Emission             consensus.EmissionSchedule
 	MaxSupply            uint64
-	RewardHalvingInterval uint64
 	BlockTime            int64
-	MaxBlockSize         uint32
-	MaxTxPerBlock        uint32
+	MaxFutureDrift       int64 // Seconds a block timestamp may lead network time (0 = unlimited)
+	CoinbaseMaturity     uint64 // Blocks a coinbase output must wait before it can be spent
+	MaxBlockSize         uint32 // Canonical serialized size limit in bytes (0 = unlimited)
+	MaxTxPerBlock        uint32 // Transaction limit, coinbase included (0 = unlimited)
+	DifficultyAlgorithm  consensus.DifficultyAlgorithm
 	DifficultyWindow     uint32
 	TargetBlockTime      uint32
+	Upgrades             consensus.UpgradeSchedule // Consensus rule changes and their activation heights
+	Deployments          []consensus.Deployment    // Miner-signaled soft forks, counted over DifficultyWindow
+	Treasury             *TreasuryConfig // Optional development fund split
+	Allocations          []GenesisAllocation
+	GenesisAddress       string // Receives the genesis block subsidy
+	GenesisNonce         uint64 // Nonce found by the genesis ceremony
+	GenesisHash          string // Pinned genesis block hash ("" = not pinned)
 }
 
 // GetMainnetGenesis returns mainnet genesis configuration
@@ -41,26 +84,137 @@ func GetMainnetGenesis() *GenesisConfig {
 		Version:               MAINNET_VERSION,
 		Timestamp:             time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix(),
 		InitialDifficulty:     0x00000FFF, // Difficulty target
-		InitialReward:         MAINNET_INITIAL_REWARD,
-		MaxSupply:             MAINNET_INITIAL_SUPPLY,
-		RewardHalvingInterval: MAINNET_REWARD_HALVING,
+		Emission: consensus.EmissionSchedule{
+			Curve:           consensus.EmissionHalving,
+			InitialReward:   MAINNET_INITIAL_REWARD,
+			HalvingInterval: MAINNET_REWARD_HALVING,
+		},
+		MaxSupply:             MAINNET_MAX_SUPPLY,
 		BlockTime:             MAINNET_BLOCK_TIME,
+		MaxFutureDrift:        MAINNET_MAX_FUTURE_DRIFT,
+		CoinbaseMaturity:      MAINNET_COINBASE_MATURITY,
 		MaxBlockSize:          MAINNET_MAX_BLOCK_SIZE,
 		MaxTxPerBlock:         MAINNET_MAX_TX_PER_BLOCK,
+		DifficultyAlgorithm:   consensus.DifficultyRetarget,
 		DifficultyWindow:      MAINNET_DIFFICULTY_WINDOW,
 		TargetBlockTime:       MAINNET_TARGET_BLOCK_TIME,
+		Upgrades: consensus.UpgradeSchedule{
+			consensus.UpgradeOutputChecks:     MAINNET_OUTPUT_CHECKS_HEIGHT,
+			consensus.UpgradeDifficultyCheck:  MAINNET_DIFFICULTY_CHECK_HEIGHT,
+			consensus.UpgradeCoinbaseHeight:   MAINNET_COINBASE_HEIGHT_HEIGHT,
+			consensus.UpgradeLockTime:         MAINNET_LOCKTIME_HEIGHT,
+			consensus.UpgradeRelativeLockTime: MAINNET_RELATIVE_LOCKTIME_HEIGHT,
+		},
+		GenesisAddress:        MAINNET_GENESIS_ADDRESS,
+		GenesisNonce:          0,
+		GenesisHash:           MAINNET_GENESIS_HASH,
+	}
+}
+
+// Validate checks the consensus parameters for consistency
+func (gc *GenesisConfig) Validate() error {
+	if gc.GenesisAddress == "" {
+		return fmt.Errorf("genesis address is required")
+	}
+
+	if gc.ChainID == "" {
+		return fmt.Errorf("chain ID is required")
+	}
+
+	if err := consensus.ValidateDifficultyAlgorithm(gc.DifficultyAlgorithm, gc.DifficultyWindow, gc.TargetBlockTime); err != nil {
+		return fmt.Errorf("invalid difficulty: %v", err)
+	}
+
+	if err := gc.Upgrades.Validate(); err != nil {
+		return fmt.Errorf("invalid upgrades: %v", err)
+	}
+
+	if err := gc.validateDeployments(); err != nil {
+		return fmt.Errorf("invalid deployments: %v", err)
+	}
+
+	if err := gc.Emission.Validate(); err != nil {
+		return fmt.Errorf("invalid emission schedule: %v", err)
+	}
+
+	if gc.Treasury != nil {
+		if err := gc.Treasury.Validate(); err != nil {
+			return fmt.Errorf("invalid treasury: %v", err)
+		}
 	}
+
+	allocated := uint64(0)
+	for i, allocation := range gc.Allocations {
+		if allocation.Address == "" || allocation.Amount == 0 {
+			return fmt.Errorf("invalid genesis allocation %d", i)
+		}
+		if allocated+allocation.Amount < allocated {
+			return fmt.Errorf("genesis allocations overflow")
+		}
+		allocated += allocation.Amount
+	}
+	if gc.MaxSupply > 0 && allocated > gc.MaxSupply {
+		return fmt.Errorf("genesis allocations %d exceed max supply %d", allocated, gc.MaxSupply)
+	}
+
+	return nil
 }
 
-// CreateGenesisBlock creates the genesis block
-func CreateGenesisBlock(minerAddress string) *Block {
-	genesis := GetMainnetGenesis()
+// TotalAllocated sums the coins paid by genesis allocations
+func (gc *GenesisConfig) TotalAllocated() uint64 {
+	total := uint64(0)
+	for _, allocation := range gc.Allocations {
+		total += allocation.Amount
+	}
+	return total
+}
+
+// NewRewardCalculator creates a reward calculator for this chain's emission schedule.
+// Genesis allocations count toward MaxSupply, so they are taken off the subsidy cap.
+// When they use up the whole supply there is no subsidy at all; a cap of 0
+// would instead mean uncapped.
+func (gc *GenesisConfig) NewRewardCalculator() *consensus.BlockRewardCalculator {
+	if gc.MaxSupply == 0 {
+		return consensus.NewBlockRewardCalculator(gc.Emission, 0)
+	}
+
+	allocated := gc.TotalAllocated()
+	if allocated >= gc.MaxSupply {
+		noSubsidy := consensus.EmissionSchedule{
+			Curve: consensus.EmissionStep,
+			Steps: []consensus.RewardStep{{StartHeight: 0, Reward: 0}},
+		}
+		return consensus.NewBlockRewardCalculator(noSubsidy, 0)
+	}
+	return consensus.NewBlockRewardCalculator(gc.Emission, gc.MaxSupply-allocated)
+}
+
+// ReadGenesisConfig loads and validates a genesis config from a JSON file
+func ReadGenesisConfig(path string) (*GenesisConfig, error) {
+	var cfg GenesisConfig
+	if _, err := readSpecFile(path, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to load genesis config: %v", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+// CreateGenesisBlock builds the genesis block from the config alone, so every
+// node derives the same block. It is not mined; the ceremony stores the
+// winning nonce in GenesisNonce.
+func CreateGenesisBlock(genesis *GenesisConfig) *Block {
+	genesisReward := genesis.NewRewardCalculator().GetBlockReward(0)
+	minerAddress := genesis.GenesisAddress
 
 	// Genesis coinbase transaction
 	coinbaseTx := &Transaction{
 		Version:   1,
 		Inputs:    []Input{{TxHash: "", OutIndex: 0}},
-		Outputs:   []Output{{Value: genesis.InitialReward, Address: minerAddress, LockScript: "OP_CHECKSIG"}},
+		Outputs:   append(genesis.CoinbaseOutputs(0, genesisReward, 0, minerAddress), genesis.allocationOutputs()...),
 		LockTime:  0,
 		Timestamp: genesis.Timestamp,
 	}
@@ -71,8 +225,8 @@ func CreateGenesisBlock(minerAddress string) *Block {
 		Version:       genesis.Version,
 		PrevBlockHash: "0",
 		Timestamp:     genesis.Timestamp,
-		Difficulty:    0x00000FFF,
-		Nonce:         0,
+		Difficulty:    genesis.InitialDifficulty,
+		Nonce:         genesis.GenesisNonce,
 		Transactions:  []*Transaction{coinbaseTx},
 		Height:        0,
 		Miner:         minerAddress,
@@ -84,10 +238,78 @@ func CreateGenesisBlock(minerAddress string) *Block {
 	return genesisBlock
 }
 
+// allocationOutputs builds the genesis outputs for the configured allocations
+func (gc *GenesisConfig) allocationOutputs() []Output {
+	outputs := make([]Output, 0, len(gc.Allocations))
+	for _, allocation := range gc.Allocations {
+		outputs = append(outputs, Output{
+			Value:        allocation.Amount,
+			Address:      allocation.Address,
+			LockScript:   "OP_CHECKSIG",
+			UnlockHeight: allocation.UnlockHeight,
+		})
+	}
+	return outputs
+}
+
+// MineGenesisBlock runs the genesis ceremony: it builds the genesis block and
+// searches for a nonce meeting the initial difficulty target
+func MineGenesisBlock(genesis *GenesisConfig) (*Block, error) {
+	block := CreateGenesisBlock(genesis)
+	block.Nonce = 0
+	if err := block.Mine(); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
+
 // TestnetGenesis returns testnet configuration
 func GetTestnetGenesis() *GenesisConfig {
 	cfg := GetMainnetGenesis()
 	cfg.ChainID = "voidex-testnet"
-	cfg.InitialReward = 10 * 100000000 // 10 coins for testing
+	cfg.Emission.InitialReward = 10 * 100000000 // 10 coins for testing
+	cfg.Upgrades = consensus.UpgradeSchedule{
+		consensus.UpgradeOutputChecks:     TESTNET_OUTPUT_CHECKS_HEIGHT,
+		consensus.UpgradeDifficultyCheck:  TESTNET_DIFFICULTY_CHECK_HEIGHT,
+		consensus.UpgradeCoinbaseHeight:   TESTNET_COINBASE_HEIGHT_HEIGHT,
+		consensus.UpgradeLockTime:         TESTNET_LOCKTIME_HEIGHT,
+		consensus.UpgradeRelativeLockTime: TESTNET_RELATIVE_LOCKTIME_HEIGHT,
+	}
+	cfg.GenesisAddress = TESTNET_GENESIS_ADDRESS
+	cfg.GenesisHash = TESTNET_GENESIS_HASH
+	return cfg
+}
+
+// GetRegtestGenesis returns regression-test configuration: trivial difficulty
+// so blocks can be generated instantly, a short halving interval, and every
+// upgrade active from genesis (see ChainParams.OverrideUpgrades)
+func GetRegtestGenesis() *GenesisConfig {
+	cfg := GetMainnetGenesis()
+	cfg.ChainID = "voidex-regtest"
+	cfg.InitialDifficulty = 0 // Any hash meets the target
+	cfg.DifficultyAlgorithm = consensus.DifficultyFixed
+	cfg.DifficultyWindow = 144 // Short version bits windows; the difficulty itself never changes
+	cfg.Upgrades = consensus.UpgradeSchedule{
+		consensus.UpgradeOutputChecks:     0,
+		consensus.UpgradeDifficultyCheck:  0,
+		consensus.UpgradeCoinbaseHeight:   0,
+		consensus.UpgradeLockTime:         0,
+		consensus.UpgradeRelativeLockTime: 0,
+	}
+	cfg.Emission.HalvingInterval = 150
+	cfg.Deployments = []consensus.Deployment{
+		{Name: "testdummy", Bit: 28, StartHeight: 0, TimeoutHeight: ^uint64(0), Threshold: 108},
+	}
+	cfg.GenesisAddress = REGTEST_GENESIS_ADDRESS
+	cfg.GenesisHash = "" // Tests may tweak params, so the genesis isn't pinned
+	return cfg
+}
+
+// GetDevnetGenesis returns configuration for short-lived developer networks
+func GetDevnetGenesis() *GenesisConfig {
+	cfg := GetTestnetGenesis()
+	cfg.ChainID = "voidex-devnet"
+	cfg.GenesisAddress = DEVNET_GENESIS_ADDRESS
+	cfg.GenesisHash = "" // Devnets are reset often, so the genesis isn't pinned
 	return cfg
 }
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestFullyAllocatedSupplyHasNoSubsidy(t *testing.T) {
+	params := RegtestParams()
+	params.Genesis.MaxSupply = 1000
+	params.Genesis.Allocations = []GenesisAllocation{{Address: "alice", Amount: 1000}}
+
+	calculator := params.Genesis.NewRewardCalculator()
+	for _, height := range []uint64{0, 1, 1000000} {
+		if reward := calculator.GetBlockReward(height); reward != 0 {
+			t.Fatalf("reward at height %d is %d", height, reward)
+		}
+	}
+
+	bc, err := NewBlockchain(newMemStorage(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Close()
+	generate(t, bc, 3, "miner")
+
+	audit, err := bc.AuditEmission()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !audit.OK() || audit.ActualSupply != 1000 {
+		t.Fatalf("supply %d after mining, audit %+v", audit.ActualSupply, audit)
+	}
+}
+
+func TestPartlyAllocatedSupplyCapsSubsidy(t *testing.T) {
+	gc := GetRegtestGenesis()
+	gc.MaxSupply = gc.Emission.InitialReward + 1000
+	gc.Allocations = []GenesisAllocation{{Address: "alice", Amount: 1000}}
+
+	calculator := gc.NewRewardCalculator()
+	if reward := calculator.GetBlockReward(0); reward != gc.Emission.InitialReward {
+		t.Fatalf("genesis reward %d", reward)
+	}
+	if reward := calculator.GetBlockReward(1); reward != 0 {
+		t.Fatalf("reward past the cap is %d", reward)
+	}
+}
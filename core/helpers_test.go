@@ -0,0 +1,135 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/consensus"
+)
+
+// memStorage is an in-memory Storage keeping only what the chain reads back:
+// blocks and state entries
+type memStorage struct {
+	blocks map[uint64]*Block
+	state  map[string][]byte
+}
+
+func newMemStorage() *memStorage {
+	return &memStorage{blocks: make(map[uint64]*Block), state: make(map[string][]byte)}
+}
+
+var errNotFound = fmt.Errorf("not found")
+
+func (ms *memStorage) StoreBlock(block *Block) error {
+	ms.blocks[block.Height] = block
+	return nil
+}
+
+func (ms *memStorage) GetBlock(height uint64) (*Block, error) {
+	if block, ok := ms.blocks[height]; ok {
+		return block, nil
+	}
+	return nil, errNotFound
+}
+
+func (ms *memStorage) GetBlockByHash(hash string) (*Block, error) {
+	for _, block := range ms.blocks {
+		if block.BlockHash == hash {
+			return block, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+func (ms *memStorage) DeleteBlock(height uint64) error {
+	delete(ms.blocks, height)
+	return nil
+}
+
+func (ms *memStorage) StoreUTXO(utxo *UTXO) error                             { return nil }
+func (ms *memStorage) GetUTXO(txHash string, outIndex uint32) (*UTXO, error)  { return nil, errNotFound }
+func (ms *memStorage) DeleteUTXO(txHash string, outIndex uint32) error        { return nil }
+func (ms *memStorage) GetAllUTXOs() ([]*UTXO, error)                          { return nil, nil }
+func (ms *memStorage) StoreTx(tx *Transaction) error                          { return nil }
+func (ms *memStorage) GetTx(txHash string) (*Transaction, error)              { return nil, errNotFound }
+func (ms *memStorage) GetTxsByAddress(address string) ([]*Transaction, error) { return nil, nil }
+
+func (ms *memStorage) StoreState(key string, value []byte) error {
+	ms.state[key] = value
+	return nil
+}
+
+func (ms *memStorage) GetState(key string) ([]byte, error) {
+	if value, ok := ms.state[key]; ok {
+		return value, nil
+	}
+	return nil, errNotFound
+}
+
+func (ms *memStorage) DeleteState(key string) error {
+	delete(ms.state, key)
+	return nil
+}
+
+func (ms *memStorage) Close() error                     { return nil }
+func (ms *memStorage) Backup() error                    { return nil }
+func (ms *memStorage) GetStats() map[string]interface{} { return nil }
+
+// newTestChain starts a regtest chain, with upgrade heights overridden by
+// upgrades if given
+func newTestChain(t *testing.T, upgrades string) *Blockchain {
+	t.Helper()
+
+	params := RegtestParams()
+	if upgrades != "" {
+		schedule, err := consensus.ParseUpgradeSchedule(upgrades)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := params.OverrideUpgrades(schedule); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bc, err := NewBlockchain(newMemStorage(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { bc.Close() })
+	return bc
+}
+
+// generate mines n blocks paying miner
+func generate(t *testing.T, bc *Blockchain, n int, miner string) []*Block {
+	t.Helper()
+
+	blocks, err := bc.GenerateBlocks(n, miner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return blocks
+}
+
+// solve recomputes the merkle root of a modified block and mines it
+func solve(t *testing.T, block *Block) *Block {
+	t.Helper()
+
+	block.MerkleRoot = block.CalculateMerkleRoot()
+	if err := block.Mine(); err != nil {
+		t.Fatal(err)
+	}
+	return block
+}
+
+// spend builds a transaction spending outputs of prev to the given values
+func spend(prev *Transaction, outIndexes []uint32, to string, values ...uint64) *Transaction {
+	tx := &Transaction{Version: 1, Timestamp: prev.Timestamp + 1}
+	for _, outIndex := range outIndexes {
+		tx.Inputs = append(tx.Inputs, Input{TxHash: prev.TxHash, OutIndex: outIndex})
+	}
+	for _, value := range values {
+		tx.Outputs = append(tx.Outputs, Output{Value: value, Address: to})
+	}
+	tx.TxHash = tx.CalculateHash()
+	return tx
+}
@@ -1,7 +1,9 @@
 package core
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 )
@@ -12,61 +14,409 @@ type MempoolTx struct {
 	AddedTime   time.Time
 	Fee         uint64
 	Priority    int64 // Higher = more likely to be included
+	Size        int   // Serialized size in bytes
+}
+
+// FeeRate returns the fee paid per byte
+func (mtx *MempoolTx) FeeRate() float64 {
+	if mtx.Size == 0 {
+		return 0
+	}
+	return float64(mtx.Fee) / float64(mtx.Size)
 }
 
 // Mempool manages pending transactions
 type Mempool struct {
-	mutex     sync.RWMutex
-	txs       map[string]*MempoolTx
-	maxSize   int
-	maxAge    time.Duration
-	feeTierSize int
+	mutex      sync.RWMutex
+	txs        map[string]*MempoolTx
+	spends     map[string]string // outpoint -> hash of the pending tx spending it
+	totalBytes int
+	policy     MempoolPolicy
+	clock      Clock
+	cancel     context.CancelFunc
+	wg         sync.WaitGroup
 }
 
-// NewMempool creates a new mempool
-func NewMempool(maxSize int, maxAge time.Duration) *Mempool {
-	mp := &Mempool{
-		txs:     make(map[string]*MempoolTx),
-		maxSize: maxSize,
-		maxAge:  maxAge,
-		feeTierSize: 1000, // transactions per fee tier
+// NewMempool creates a new mempool. Limits left at zero in policy take their
+// default values. Call Start to run periodic expiry.
+func NewMempool(policy MempoolPolicy) (*Mempool, error) {
+	policy = policy.withDefaults()
+	if err := policy.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid mempool policy: %v", err)
 	}
 
-	// Start cleanup goroutine
-	go mp.cleanupExpired()
-	return mp
+	return &Mempool{
+		txs:    make(map[string]*MempoolTx),
+		spends: make(map[string]string),
+		policy: policy,
+		clock:  systemClock{},
+	}, nil
 }
 
-// AddTransaction adds a transaction to mempool
-func (mp *Mempool) AddTransaction(tx *Transaction, fee uint64) error {
+// SetClock replaces the time source used for entry times and expiry
+func (mp *Mempool) SetClock(clock Clock) {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+	mp.clock = clock
+}
+
+// Policy returns the mempool policy
+func (mp *Mempool) Policy() MempoolPolicy {
+	return mp.policy
+}
+
+// Start runs periodic expiry until ctx is cancelled or Stop is called
+func (mp *Mempool) Start(ctx context.Context) {
 	mp.mutex.Lock()
 	defer mp.mutex.Unlock()
 
-	if len(mp.txs) >= mp.maxSize {
-		return fmt.Errorf("mempool full, max size: %d", mp.maxSize)
+	if mp.cancel != nil {
+		return // Already running
 	}
 
-	if _, exists := mp.txs[tx.TxHash]; exists {
-		return fmt.Errorf("transaction already in mempool: %s", tx.TxHash)
+	ctx, mp.cancel = context.WithCancel(ctx)
+	mp.wg.Add(1)
+	go mp.cleanupExpired(ctx)
+}
+
+// Stop halts periodic expiry and waits for it to finish
+func (mp *Mempool) Stop() {
+	mp.mutex.Lock()
+	cancel := mp.cancel
+	mp.cancel = nil
+	mp.mutex.Unlock()
+
+	if cancel != nil {
+		cancel()
 	}
+	mp.wg.Wait()
+}
+
+// AddTransaction adds a transaction to mempool
+func (mp *Mempool) AddTransaction(tx *Transaction, fee uint64) error {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
 
 	priority := int64(fee) / int64(len(tx.Inputs)+len(tx.Outputs))
 
-	mp.txs[tx.TxHash] = &MempoolTx{
+	return mp.admit(&MempoolTx{
 		Tx:        tx,
-		AddedTime: time.Now(),
+		AddedTime: mp.clock.Now(),
 		Fee:       fee,
 		Priority:  priority,
+		Size:      tx.Size(),
+	})
+}
+
+// RestoreTransaction re-inserts a persisted entry, keeping its original fee and entry time
+func (mp *Mempool) RestoreTransaction(entry *MempoolTx) error {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+
+	if mp.clock.Now().Sub(entry.AddedTime) > mp.policy.MaxAge {
+		return fmt.Errorf("transaction expired: %s", entry.Tx.TxHash)
+	}
+
+	return mp.admit(&MempoolTx{
+		Tx:        entry.Tx,
+		AddedTime: entry.AddedTime,
+		Fee:       entry.Fee,
+		Priority:  entry.Priority,
+		Size:      entry.Tx.Size(),
+	})
+}
+
+// admit applies the mempool policy and inserts the entry; the caller must hold the lock
+func (mp *Mempool) admit(entry *MempoolTx) error {
+	tx := entry.Tx
+
+	if _, exists := mp.txs[tx.TxHash]; exists {
+		return fmt.Errorf("transaction already in mempool: %s", tx.TxHash)
+	}
+
+	if err := mp.checkConflicts(tx); err != nil {
+		return err
 	}
 
+	if entry.FeeRate() < mp.policy.MinRelayFeeRate {
+		return fmt.Errorf("fee rate %.2f below minimum relay fee rate %.2f: %s",
+			entry.FeeRate(), mp.policy.MinRelayFeeRate, tx.TxHash)
+	}
+
+	ancestors := mp.ancestors(tx)
+	if len(ancestors)+1 > mp.policy.MaxAncestors {
+		return fmt.Errorf("too many unconfirmed ancestors: %d, max: %d", len(ancestors)+1, mp.policy.MaxAncestors)
+	}
+
+	ancestorBytes := entry.Size
+	for _, ancestor := range ancestors {
+		ancestorBytes += ancestor.Size
+	}
+	if ancestorBytes > mp.policy.MaxAncestorBytes {
+		return fmt.Errorf("unconfirmed ancestors too large: %d bytes, max: %d", ancestorBytes, mp.policy.MaxAncestorBytes)
+	}
+
+	if err := mp.makeRoom(entry, ancestors); err != nil {
+		return err
+	}
+
+	mp.insert(entry)
+	return nil
+}
+
+// ancestors returns the pending transactions tx depends on, directly or indirectly
+func (mp *Mempool) ancestors(tx *Transaction) map[string]*MempoolTx {
+	result := make(map[string]*MempoolTx)
+	stack := []*Transaction{tx}
+
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		for _, input := range current.Inputs {
+			parent, pending := mp.txs[input.TxHash]
+			if !pending {
+				continue
+			}
+			if _, seen := result[input.TxHash]; seen {
+				continue
+			}
+			result[input.TxHash] = parent
+			stack = append(stack, parent.Tx)
+		}
+	}
+
+	return result
+}
+
+// makeRoom evicts the cheapest transactions, with their descendants, until entry
+// fits in MaxBytes. Nothing is evicted unless entry pays a higher fee rate than
+// every victim, descendants included; ancestors of entry are never evicted. The
+// victims are chosen before anything is removed, so a rejected entry leaves the
+// mempool unchanged.
+func (mp *Mempool) makeRoom(entry *MempoolTx, ancestors map[string]*MempoolTx) error {
+	if entry.Size > mp.policy.MaxBytes {
+		return fmt.Errorf("transaction larger than mempool: %d bytes", entry.Size)
+	}
+	if mp.totalBytes+entry.Size <= mp.policy.MaxBytes {
+		return nil
+	}
+
+	var candidates []*MempoolTx
+	for txHash, mempoolTx := range mp.txs {
+		if _, isAncestor := ancestors[txHash]; !isAncestor {
+			candidates = append(candidates, mempoolTx)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].FeeRate() < candidates[j].FeeRate()
+	})
+
+	victims := make(map[string]*MempoolTx)
+	freed := 0
+	for _, candidate := range candidates {
+		if mp.totalBytes-freed+entry.Size <= mp.policy.MaxBytes {
+			break
+		}
+		if candidate.FeeRate() >= entry.FeeRate() {
+			break
+		}
+		if _, chosen := victims[candidate.Tx.TxHash]; chosen {
+			continue // Already a descendant of an earlier victim
+		}
+
+		// A candidate can only go with all its descendants, so skip it if
+		// any of them pays as much as entry
+		group := make(map[string]*MempoolTx)
+		mp.collectDescendants(candidate.Tx.TxHash, group)
+		affordable := true
+		for _, member := range group {
+			if member.FeeRate() >= entry.FeeRate() {
+				affordable = false
+				break
+			}
+		}
+		if !affordable {
+			continue
+		}
+
+		for txHash, member := range group {
+			if _, chosen := victims[txHash]; !chosen {
+				victims[txHash] = member
+				freed += member.Size
+			}
+		}
+	}
+
+	if mp.totalBytes-freed+entry.Size > mp.policy.MaxBytes {
+		return fmt.Errorf("mempool full, max bytes: %d", mp.policy.MaxBytes)
+	}
+
+	for txHash := range victims {
+		mp.remove(txHash)
+	}
 	return nil
 }
 
+// collectDescendants adds txHash and every pending transaction depending on it to into
+func (mp *Mempool) collectDescendants(txHash string, into map[string]*MempoolTx) {
+	mempoolTx, exists := mp.txs[txHash]
+	if !exists {
+		return
+	}
+	if _, seen := into[txHash]; seen {
+		return
+	}
+	into[txHash] = mempoolTx
+
+	for i := range mempoolTx.Tx.Outputs {
+		if child, spent := mp.spends[outpointKey(txHash, uint32(i))]; spent {
+			mp.collectDescendants(child, into)
+		}
+	}
+}
+
+// checkConflicts rejects a transaction spending an output another pending tx already spends
+func (mp *Mempool) checkConflicts(tx *Transaction) error {
+	for _, input := range tx.Inputs {
+		if spender, spent := mp.spends[outpointKey(input.TxHash, input.OutIndex)]; spent {
+			return fmt.Errorf("transaction %s conflicts with mempool transaction %s", tx.TxHash, spender)
+		}
+	}
+	return nil
+}
+
+// insert adds an entry and indexes the outputs it spends
+func (mp *Mempool) insert(entry *MempoolTx) {
+	mp.txs[entry.Tx.TxHash] = entry
+	mp.totalBytes += entry.Size
+	for _, input := range entry.Tx.Inputs {
+		mp.spends[outpointKey(input.TxHash, input.OutIndex)] = entry.Tx.TxHash
+	}
+}
+
+// remove deletes a single entry and its spend index
+func (mp *Mempool) remove(txHash string) bool {
+	mempoolTx, exists := mp.txs[txHash]
+	if !exists {
+		return false
+	}
+
+	for _, input := range mempoolTx.Tx.Inputs {
+		key := outpointKey(input.TxHash, input.OutIndex)
+		if mp.spends[key] == txHash {
+			delete(mp.spends, key)
+		}
+	}
+	mp.totalBytes -= mempoolTx.Size
+	delete(mp.txs, txHash)
+	return true
+}
+
+// removeDescendants removes every pending transaction that spends outputs of txHash, recursively
+func (mp *Mempool) removeDescendants(txHash string, numOutputs int) int {
+	removed := 0
+	for i := 0; i < numOutputs; i++ {
+		child, spent := mp.spends[outpointKey(txHash, uint32(i))]
+		if !spent {
+			continue
+		}
+		removed += mp.removeWithDescendants(child)
+	}
+	return removed
+}
+
+// removeWithDescendants removes a transaction and everything that depends on it
+func (mp *Mempool) removeWithDescendants(txHash string) int {
+	mempoolTx, exists := mp.txs[txHash]
+	if !exists {
+		return 0
+	}
+
+	mp.remove(txHash)
+	return 1 + mp.removeDescendants(txHash, len(mempoolTx.Tx.Outputs))
+}
+
 // RemoveTransaction removes transaction from mempool
 func (mp *Mempool) RemoveTransaction(txHash string) {
 	mp.mutex.Lock()
 	defer mp.mutex.Unlock()
-	delete(mp.txs, txHash)
+	mp.remove(txHash)
+}
+
+// RemoveDescendants removes all pending transactions spending outputs of tx, recursively
+func (mp *Mempool) RemoveDescendants(tx *Transaction) int {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+	return mp.removeDescendants(tx.TxHash, len(tx.Outputs))
+}
+
+// RemoveForBlock removes transactions confirmed by block and evicts every pending
+// transaction that double-spends one of its inputs, along with its descendants.
+// It returns the number of conflicting transactions evicted.
+func (mp *Mempool) RemoveForBlock(block *Block) int {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+
+	evicted := 0
+	for _, tx := range block.Transactions {
+		mp.remove(tx.TxHash)
+
+		if tx.IsCoinbase() {
+			continue
+		}
+
+		for _, input := range tx.Inputs {
+			if conflict, spent := mp.spends[outpointKey(input.TxHash, input.OutIndex)]; spent {
+				evicted += mp.removeWithDescendants(conflict)
+			}
+		}
+	}
+
+	return evicted
+}
+
+// FindUTXO returns an output created by a pending transaction
+func (mp *Mempool) FindUTXO(txHash string, outIndex uint32) *UTXO {
+	mp.mutex.RLock()
+	defer mp.mutex.RUnlock()
+
+	mempoolTx, exists := mp.txs[txHash]
+	if !exists || int(outIndex) >= len(mempoolTx.Tx.Outputs) {
+		return nil
+	}
+
+	return newUTXO(mempoolTx.Tx, outIndex, MempoolHeight)
+}
+
+// SpentBy returns the pending transaction spending an output, if any
+func (mp *Mempool) SpentBy(txHash string, outIndex uint32) (string, bool) {
+	mp.mutex.RLock()
+	defer mp.mutex.RUnlock()
+	spender, spent := mp.spends[outpointKey(txHash, outIndex)]
+	return spender, spent
+}
+
+// View returns a UTXO view of chain with the pending transactions applied on top
+func (mp *Mempool) View(chain UTXOView) UTXOView {
+	return &mempoolView{chain: chain, pool: mp}
+}
+
+// mempoolView resolves outputs against the mempool first, then the chain
+type mempoolView struct {
+	chain UTXOView
+	pool  *Mempool
+}
+
+// FindUTXO returns an output unless a pending transaction already spends it
+func (mv *mempoolView) FindUTXO(txHash string, outIndex uint32) *UTXO {
+	if _, spent := mv.pool.SpentBy(txHash, outIndex); spent {
+		return nil
+	}
+	if utxo := mv.pool.FindUTXO(txHash, outIndex); utxo != nil {
+		return utxo
+	}
+	return mv.chain.FindUTXO(txHash, outIndex)
 }
 
 // GetTransaction retrieves a transaction from mempool
@@ -104,6 +454,24 @@ func (mp *Mempool) GetTransactions(limit int) []*Transaction {
 	return result
 }
 
+// Snapshot returns a copy of all mempool entries, oldest first
+func (mp *Mempool) Snapshot() []*MempoolTx {
+	mp.mutex.RLock()
+	defer mp.mutex.RUnlock()
+
+	entries := make([]*MempoolTx, 0, len(mp.txs))
+	for _, mempoolTx := range mp.txs {
+		entry := *mempoolTx
+		entries = append(entries, &entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].AddedTime.Before(entries[j].AddedTime)
+	})
+
+	return entries
+}
+
 // Size returns number of transactions in mempool
 func (mp *Mempool) Size() int {
 	mp.mutex.RLock()
@@ -111,22 +479,58 @@ func (mp *Mempool) Size() int {
 	return len(mp.txs)
 }
 
-// cleanupExpired removes old transactions
-func (mp *Mempool) cleanupExpired() {
-	ticker := time.NewTicker(5 * time.Minute)
-	defer ticker.Stop()
+// Bytes returns the total serialized size of pending transactions
+func (mp *Mempool) Bytes() int {
+	mp.mutex.RLock()
+	defer mp.mutex.RUnlock()
+	return mp.totalBytes
+}
 
-	for range ticker.C {
-		mp.mutex.Lock()
+// ExpireOld removes entries older than the policy max age, along with their
+// descendants, and returns how many were removed
+func (mp *Mempool) ExpireOld() int {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
 
-		now := time.Now()
-		for txHash, mempoolTx := range mp.txs {
-			if now.Sub(mempoolTx.AddedTime) > mp.maxAge {
-				delete(mp.txs, txHash)
-			}
+	now := mp.clock.Now()
+	removed := 0
+	for txHash, mempoolTx := range mp.txs {
+		if now.Sub(mempoolTx.AddedTime) > mp.policy.MaxAge {
+			removed += mp.removeWithDescendants(txHash)
+		}
+	}
+	return removed
+}
+
+// RemoveExpiredAt removes transactions whose ExpiryHeight is below height,
+// with their descendants, and returns the number removed
+func (mp *Mempool) RemoveExpiredAt(height uint64) int {
+	mp.mutex.Lock()
+	defer mp.mutex.Unlock()
+
+	removed := 0
+	for txHash, mempoolTx := range mp.txs {
+		if mempoolTx.Tx.IsExpired(height) {
+			removed += mp.removeWithDescendants(txHash)
 		}
+	}
+	return removed
+}
+
+// cleanupExpired removes old transactions until ctx is cancelled
+func (mp *Mempool) cleanupExpired(ctx context.Context) {
+	defer mp.wg.Done()
 
-		mp.mutex.Unlock()
+	ticker := time.NewTicker(mp.policy.CleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			mp.ExpireOld()
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
@@ -0,0 +1,136 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// mempoolStateKey is the storage state key holding the persisted mempool
+	mempoolStateKey = "mempool"
+
+	// mempoolPersistInterval is how often the mempool is flushed to disk
+	mempoolPersistInterval = 10 * time.Minute
+)
+
+// SaveMempool writes every pending transaction, with its fee and entry time, to storage
+func (bc *Blockchain) SaveMempool() error {
+	entries := bc.PendingTransactions.Snapshot()
+
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return fmt.Errorf("failed to encode mempool: %v", err)
+	}
+
+	if err := bc.Chain.StoreState(mempoolStateKey, data); err != nil {
+		return fmt.Errorf("failed to store mempool: %v", err)
+	}
+
+	return nil
+}
+
+// LoadMempool restores the persisted mempool and revalidates it against the current tip.
+// Entries older than the mempool max age, or no longer valid, are dropped.
+func (bc *Blockchain) LoadMempool() error {
+	data, err := bc.Chain.GetState(mempoolStateKey)
+	if err != nil || len(data) == 0 {
+		// Nothing persisted yet
+		return nil
+	}
+
+	var entries []*MempoolTx
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return fmt.Errorf("failed to decode mempool: %v", err)
+	}
+
+	bc.mutex.RLock()
+	defer bc.mutex.RUnlock()
+
+	// Entry times don't order parents before children: a parent returned to
+	// the mempool by a reorg is newer than its pending child. Keep passing over
+	// the remaining entries until no more can be restored, so a child skipped
+	// in one pass is picked up once its parent is back.
+	view := newUTXOOverlay(bc.UTXOSet)
+	nextHeight := uint64(len(bc.Blocks))
+	restored, dropped := 0, 0
+
+	for progress := true; progress; {
+		progress = false
+		remaining := entries[:0]
+
+		for _, entry := range entries {
+			if entry == nil || entry.Tx == nil {
+				dropped++
+				continue
+			}
+
+			tx := entry.Tx
+			if tx.IsCoinbase() || tx.CalculateHash() != tx.TxHash {
+				dropped++
+				continue
+			}
+			if !tx.HasAllInputs(view) {
+				remaining = append(remaining, entry)
+				continue
+			}
+
+			if !tx.Validate(view, bc.GenesisConfig.Upgrades, nextHeight) ||
+				bc.checkContext(tx, view, nextHeight, true) != nil {
+				dropped++
+				continue
+			}
+
+			if err := bc.PendingTransactions.RestoreTransaction(entry); err != nil {
+				dropped++
+				continue
+			}
+
+			connectTransaction(view, tx, MempoolHeight)
+			restored++
+			progress = true
+		}
+
+		entries = remaining
+	}
+	dropped += len(entries)
+
+	fmt.Printf("[Mempool] Restored %d transactions, dropped %d\n", restored, dropped)
+	return nil
+}
+
+// persistMempoolLoop periodically flushes the mempool and fee estimates until Close is called
+func (bc *Blockchain) persistMempoolLoop() {
+	defer bc.wg.Done()
+
+	ticker := time.NewTicker(mempoolPersistInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := bc.SaveMempool(); err != nil {
+				fmt.Printf("[Mempool] Periodic save failed: %v\n", err)
+			}
+			if err := bc.SaveFeeEstimates(); err != nil {
+				fmt.Printf("[FeeEstimator] Periodic save failed: %v\n", err)
+			}
+		case <-bc.quit:
+			return
+		}
+	}
+}
+
+// Close stops background work and persists the mempool and fee estimates for the next start
+func (bc *Blockchain) Close() error {
+	bc.closeOnce.Do(func() {
+		close(bc.quit)
+	})
+	bc.wg.Wait()
+	bc.PendingTransactions.Stop()
+
+	if err := bc.SaveFeeEstimates(); err != nil {
+		return err
+	}
+	return bc.SaveMempool()
+}
@@ -0,0 +1,53 @@
+package core
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMempoolRestoresParentsReaddedByReorg(t *testing.T) {
+	store := newMemStorage()
+	bc, err := NewBlockchain(store, RegtestParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := generate(t, bc, 101, "miner")
+
+	clock := &fakeClock{now: time.Now()}
+	bc.SetTimeSource(NewMedianTimeSource(clock, DefaultMaxTimeOffset))
+
+	coinbase := blocks[0].Transactions[0]
+	parent := spend(coinbase, []uint32{0}, "alice", coinbase.Outputs[0].Value-10000)
+	if err := bc.AddPendingTransaction(parent); err != nil {
+		t.Fatal(err)
+	}
+	generate(t, bc, 1, "miner")
+
+	clock.now = clock.now.Add(time.Minute)
+	child := spend(parent, []uint32{0}, "bob", parent.Outputs[0].Value-10000)
+	if err := bc.AddPendingTransaction(child); err != nil {
+		t.Fatal(err)
+	}
+
+	// The parent returns to the mempool after its child entered it
+	clock.now = clock.now.Add(time.Minute)
+	if _, err := bc.DisconnectTip(); err != nil {
+		t.Fatal(err)
+	}
+	if bc.PendingTransactions.Size() != 2 {
+		t.Fatalf("%d transactions pending after disconnect", bc.PendingTransactions.Size())
+	}
+	if err := bc.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	restarted, err := NewBlockchain(store, RegtestParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer restarted.Close()
+	if !restarted.PendingTransactions.HasTransaction(parent.TxHash) ||
+		!restarted.PendingTransactions.HasTransaction(child.TxHash) {
+		t.Fatalf("restored %d of 2 transactions", restarted.PendingTransactions.Size())
+	}
+}
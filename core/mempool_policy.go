@@ -0,0 +1,86 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// MempoolPolicy controls which transactions the mempool accepts and how long it keeps them
+type MempoolPolicy struct {
+	MinRelayFeeRate  float64       // Minimum fee rate, satoshis per byte
+	MaxBytes         int           // Total serialized size of all pending transactions
+	MaxAge           time.Duration // Entries older than this are expired
+	MaxAncestors     int           // Max unconfirmed ancestors, counting the tx itself
+	MaxAncestorBytes int           // Max size of a tx plus its unconfirmed ancestors
+	CleanupInterval  time.Duration // How often expired entries are removed
+}
+
+// DefaultMempoolPolicy returns the standard relay policy
+func DefaultMempoolPolicy() MempoolPolicy {
+	return MempoolPolicy{
+		MinRelayFeeRate:  1,
+		MaxBytes:         300 * 1000 * 1000, // 300MB
+		MaxAge:           24 * time.Hour,
+		MaxAncestors:     25,
+		MaxAncestorBytes: 101 * 1000,
+		CleanupInterval:  5 * time.Minute,
+	}
+}
+
+// withDefaults fills unset limits from the default policy. A zero
+// MinRelayFeeRate is kept, as it means free relay.
+func (p MempoolPolicy) withDefaults() MempoolPolicy {
+	defaults := DefaultMempoolPolicy()
+	if p.MaxBytes == 0 {
+		p.MaxBytes = defaults.MaxBytes
+	}
+	if p.MaxAge == 0 {
+		p.MaxAge = defaults.MaxAge
+	}
+	if p.MaxAncestors == 0 {
+		p.MaxAncestors = defaults.MaxAncestors
+	}
+	if p.MaxAncestorBytes == 0 {
+		p.MaxAncestorBytes = defaults.MaxAncestorBytes
+	}
+	if p.CleanupInterval == 0 {
+		p.CleanupInterval = defaults.CleanupInterval
+	}
+	return p
+}
+
+// Validate checks that every limit of the policy is usable
+func (p MempoolPolicy) Validate() error {
+	if p.MinRelayFeeRate < 0 {
+		return fmt.Errorf("min relay fee rate must not be negative: %v", p.MinRelayFeeRate)
+	}
+	if p.MaxBytes <= 0 {
+		return fmt.Errorf("max mempool bytes must be positive: %d", p.MaxBytes)
+	}
+	if p.MaxAge <= 0 {
+		return fmt.Errorf("max age must be positive: %v", p.MaxAge)
+	}
+	if p.MaxAncestors <= 0 {
+		return fmt.Errorf("max ancestors must be positive: %d", p.MaxAncestors)
+	}
+	if p.MaxAncestorBytes <= 0 {
+		return fmt.Errorf("max ancestor bytes must be positive: %d", p.MaxAncestorBytes)
+	}
+	if p.CleanupInterval <= 0 {
+		return fmt.Errorf("cleanup interval must be positive: %v", p.CleanupInterval)
+	}
+	return nil
+}
+
+// Clock supplies the current time; tests can substitute a fake one
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock reads the local wall clock
+type systemClock struct{}
+
+// Now returns the local time
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
@@ -0,0 +1,220 @@
+package core
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock tests move by hand
+type fakeClock struct {
+	now time.Time
+}
+
+func (fc *fakeClock) Now() time.Time {
+	return fc.now
+}
+
+// pendingTx builds a two-output transaction spending output outIndex of parent
+func pendingTx(parent string, outIndex uint32, value uint64) *Transaction {
+	tx := &Transaction{
+		Version: 1,
+		Inputs:  []Input{{TxHash: parent, OutIndex: outIndex}},
+		Outputs: []Output{{Value: value, Address: "alice"}, {Value: 1, Address: "bob"}},
+	}
+	tx.TxHash = tx.CalculateHash()
+	return tx
+}
+
+func newTestMempool(t *testing.T, policy MempoolPolicy) (*Mempool, *fakeClock) {
+	t.Helper()
+
+	mp, err := NewMempool(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	mp.SetClock(clock)
+	return mp, clock
+}
+
+func TestMempoolPolicyDefaults(t *testing.T) {
+	mp, _ := newTestMempool(t, MempoolPolicy{MinRelayFeeRate: 2})
+
+	policy := mp.Policy()
+	defaults := DefaultMempoolPolicy()
+	if policy.MinRelayFeeRate != 2 {
+		t.Fatalf("min relay fee rate changed to %v", policy.MinRelayFeeRate)
+	}
+	if policy.CleanupInterval != defaults.CleanupInterval || policy.MaxAncestors != defaults.MaxAncestors ||
+		policy.MaxBytes != defaults.MaxBytes || policy.MaxAge != defaults.MaxAge ||
+		policy.MaxAncestorBytes != defaults.MaxAncestorBytes {
+		t.Fatalf("unset limits not defaulted: %+v", policy)
+	}
+
+	// A policy missing its cleanup interval must not crash Start
+	mp.Start(context.Background())
+	mp.Stop()
+
+	if err := mp.AddTransaction(pendingTx("funding", 0, 5), 1000); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMempoolPolicyValidate(t *testing.T) {
+	if err := DefaultMempoolPolicy().Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	invalid := []func(*MempoolPolicy){
+		func(p *MempoolPolicy) { p.MinRelayFeeRate = -1 },
+		func(p *MempoolPolicy) { p.MaxBytes = -1 },
+		func(p *MempoolPolicy) { p.MaxAge = -time.Second },
+		func(p *MempoolPolicy) { p.MaxAncestors = 0 },
+		func(p *MempoolPolicy) { p.MaxAncestorBytes = -1 },
+		func(p *MempoolPolicy) { p.CleanupInterval = -time.Second },
+	}
+	for i, change := range invalid {
+		policy := DefaultMempoolPolicy()
+		change(&policy)
+		if err := policy.Validate(); err == nil {
+			t.Fatalf("policy %d accepted: %+v", i, policy)
+		}
+		if policy.MaxAncestors != 0 {
+			if _, err := NewMempool(policy); err == nil {
+				t.Fatalf("mempool created with policy %d", i)
+			}
+		}
+	}
+}
+
+func TestMempoolExpiryUsesClock(t *testing.T) {
+	policy := DefaultMempoolPolicy()
+	policy.MaxAge = time.Hour
+	mp, clock := newTestMempool(t, policy)
+
+	parent := pendingTx("funding", 0, 5)
+	child := pendingTx(parent.TxHash, 0, 4)
+	if err := mp.AddTransaction(parent, 1000); err != nil {
+		t.Fatal(err)
+	}
+	clock.now = clock.now.Add(30 * time.Minute)
+	if err := mp.AddTransaction(child, 1000); err != nil {
+		t.Fatal(err)
+	}
+
+	clock.now = clock.now.Add(31 * time.Minute)
+	if n := mp.ExpireOld(); n != 2 || mp.Size() != 0 || mp.Bytes() != 0 {
+		t.Fatalf("expired %d, %d left using %d bytes", n, mp.Size(), mp.Bytes())
+	}
+}
+
+func TestMempoolConflictsAndEviction(t *testing.T) {
+	mp, _ := newTestMempool(t, DefaultMempoolPolicy())
+
+	parent := pendingTx("funding", 0, 5)
+	child := pendingTx(parent.TxHash, 0, 4)
+	conflict := pendingTx("funding", 0, 3)
+	if err := mp.AddTransaction(parent, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if err := mp.AddTransaction(child, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if err := mp.AddTransaction(conflict, 1000); err == nil {
+		t.Fatal("double spend accepted")
+	}
+
+	// A block confirming the conflict evicts the parent and its child
+	block := &Block{Transactions: []*Transaction{conflict}}
+	if n := mp.RemoveForBlock(block); n != 2 || mp.Size() != 0 {
+		t.Fatalf("evicted %d, %d left", n, mp.Size())
+	}
+
+	// A full mempool evicts the lowest fee rate for a better paying transaction
+	policy := DefaultMempoolPolicy()
+	policy.MaxBytes = parent.Size() + 10
+	full, _ := newTestMempool(t, policy)
+	if err := full.AddTransaction(parent, 200); err != nil {
+		t.Fatal(err)
+	}
+	other := pendingTx("other", 0, 5)
+	if err := full.AddTransaction(other, 100); err == nil {
+		t.Fatal("lower fee rate accepted into a full mempool")
+	}
+	if err := full.AddTransaction(other, 1000); err != nil || full.HasTransaction(parent.TxHash) {
+		t.Fatalf("expected eviction, got %v", err)
+	}
+}
+
+// snapshotHashes lists the transactions in the mempool
+func snapshotHashes(mp *Mempool) map[string]bool {
+	hashes := make(map[string]bool)
+	for _, entry := range mp.Snapshot() {
+		hashes[entry.Tx.TxHash] = true
+	}
+	return hashes
+}
+
+func TestMempoolFailedAdmissionEvictsNothing(t *testing.T) {
+	cheap := pendingTx("funding", 0, 5)
+	rich := pendingTx("funding", 1, 5)
+	size := cheap.Size()
+
+	policy := DefaultMempoolPolicy()
+	policy.MaxBytes = 2 * size
+	mp, _ := newTestMempool(t, policy)
+	if err := mp.AddTransaction(cheap, uint64(size)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mp.AddTransaction(rich, uint64(10*size)); err != nil {
+		t.Fatal(err)
+	}
+	before := snapshotHashes(mp)
+
+	// Needs both slots but only outbids the cheaper transaction
+	large := pendingTx("funding", 2, 5)
+	large.Outputs = append(large.Outputs, Output{Value: 1, Address: "carol"})
+	large.TxHash = large.CalculateHash()
+	if err := mp.AddTransaction(large, uint64(5*large.Size())); err == nil {
+		t.Fatal("transaction admitted without room")
+	}
+
+	after := snapshotHashes(mp)
+	if len(after) != len(before) || !after[cheap.TxHash] || !after[rich.TxHash] || mp.Bytes() != 2*size {
+		t.Fatalf("failed admission changed the mempool: %v", after)
+	}
+}
+
+func TestMempoolEvictionSparesRicherDescendants(t *testing.T) {
+	parent := pendingTx("funding", 0, 5)
+	child := pendingTx(parent.TxHash, 0, 4)
+	size := parent.Size()
+
+	policy := DefaultMempoolPolicy()
+	policy.MaxBytes = parent.Size() + child.Size()
+	mp, _ := newTestMempool(t, policy)
+	if err := mp.AddTransaction(parent, uint64(size)); err != nil {
+		t.Fatal(err)
+	}
+	if err := mp.AddTransaction(child, uint64(10*child.Size())); err != nil {
+		t.Fatal(err)
+	}
+
+	// Outbids the parent, but evicting it would take the better paying child
+	other := pendingTx("other", 0, 5)
+	if err := mp.AddTransaction(other, uint64(5*other.Size())); err == nil {
+		t.Fatal("evicted a descendant paying more than the new transaction")
+	}
+	if !mp.HasTransaction(parent.TxHash) || !mp.HasTransaction(child.TxHash) {
+		t.Fatal("failed admission changed the mempool")
+	}
+
+	// Outbidding both evicts the parent with its child
+	if err := mp.AddTransaction(other, uint64(20*other.Size())); err != nil {
+		t.Fatal(err)
+	}
+	if mp.HasTransaction(parent.TxHash) || mp.HasTransaction(child.TxHash) || mp.Size() != 1 {
+		t.Fatal("parent and child not evicted together")
+	}
+}
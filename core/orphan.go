@@ -0,0 +1,197 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// MaxOrphanTxSize is the largest transaction, in bytes, kept as an orphan.
+// Orphans can't be validated, so this bounds the memory a peer can tie up.
+const MaxOrphanTxSize = 100 * 1000
+
+// OrphanTx is a transaction waiting for one or more of its parents
+type OrphanTx struct {
+	Tx        *Transaction
+	Peer      string   // Peer that relayed the transaction
+	Missing   []string // Outpoints not yet known
+	AddedTime time.Time
+}
+
+// OrphanPool holds transactions that spend outputs we haven't seen yet
+type OrphanPool struct {
+	mutex      sync.Mutex
+	orphans    map[string]*OrphanTx
+	byOutpoint map[string]map[string]bool // missing outpoint -> orphan hashes
+	perPeer    map[string]int
+	maxOrphans int
+	maxPerPeer int
+	expiry     time.Duration
+	clock      Clock
+}
+
+// NewOrphanPool creates a bounded orphan pool
+func NewOrphanPool(maxOrphans, maxPerPeer int, expiry time.Duration) *OrphanPool {
+	return &OrphanPool{
+		orphans:    make(map[string]*OrphanTx),
+		byOutpoint: make(map[string]map[string]bool),
+		perPeer:    make(map[string]int),
+		maxOrphans: maxOrphans,
+		maxPerPeer: maxPerPeer,
+		expiry:     expiry,
+		clock:      systemClock{},
+	}
+}
+
+// SetClock replaces the time source used for orphan ages
+func (op *OrphanPool) SetClock(clock Clock) {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+	op.clock = clock
+}
+
+// AddOrphan stores tx until the outputs in missing become available
+func (op *OrphanPool) AddOrphan(tx *Transaction, peer string, missing []string) error {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+
+	if _, exists := op.orphans[tx.TxHash]; exists {
+		return fmt.Errorf("orphan already known: %s", tx.TxHash)
+	}
+
+	if size := tx.Size(); size > MaxOrphanTxSize {
+		return fmt.Errorf("orphan too large: %d bytes, max: %d", size, MaxOrphanTxSize)
+	}
+
+	op.expireLocked()
+
+	if op.perPeer[peer] >= op.maxPerPeer {
+		return fmt.Errorf("peer %q exceeded orphan limit: %d", peer, op.maxPerPeer)
+	}
+
+	// Make room by evicting the oldest orphan
+	if len(op.orphans) >= op.maxOrphans {
+		var oldest *OrphanTx
+		for _, orphan := range op.orphans {
+			if oldest == nil || orphan.AddedTime.Before(oldest.AddedTime) {
+				oldest = orphan
+			}
+		}
+		if oldest != nil {
+			op.removeLocked(oldest.Tx.TxHash)
+		}
+	}
+
+	op.orphans[tx.TxHash] = &OrphanTx{
+		Tx:        tx,
+		Peer:      peer,
+		Missing:   missing,
+		AddedTime: op.clock.Now(),
+	}
+	for _, key := range missing {
+		if op.byOutpoint[key] == nil {
+			op.byOutpoint[key] = make(map[string]bool)
+		}
+		op.byOutpoint[key][tx.TxHash] = true
+	}
+	op.perPeer[peer]++
+
+	return nil
+}
+
+// TakeChildren removes and returns the orphans waiting on any output of parent
+func (op *OrphanPool) TakeChildren(parent *Transaction) []*OrphanTx {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+
+	var children []*OrphanTx
+	for i := range parent.Outputs {
+		for txHash := range op.byOutpoint[outpointKey(parent.TxHash, uint32(i))] {
+			if orphan, exists := op.orphans[txHash]; exists {
+				children = append(children, orphan)
+				op.removeLocked(txHash)
+			}
+		}
+	}
+
+	return children
+}
+
+// RemoveOrphan drops a single orphan
+func (op *OrphanPool) RemoveOrphan(txHash string) {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+	op.removeLocked(txHash)
+}
+
+// RemoveOrphansForPeer drops every orphan relayed by peer (e.g. on disconnect)
+func (op *OrphanPool) RemoveOrphansForPeer(peer string) int {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+
+	removed := 0
+	for txHash, orphan := range op.orphans {
+		if orphan.Peer == peer {
+			op.removeLocked(txHash)
+			removed++
+		}
+	}
+	return removed
+}
+
+// ExpireOrphans drops orphans older than the pool expiry
+func (op *OrphanPool) ExpireOrphans() int {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+	return op.expireLocked()
+}
+
+// HasOrphan checks if a transaction is waiting in the orphan pool
+func (op *OrphanPool) HasOrphan(txHash string) bool {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+	_, exists := op.orphans[txHash]
+	return exists
+}
+
+// Size returns number of orphans
+func (op *OrphanPool) Size() int {
+	op.mutex.Lock()
+	defer op.mutex.Unlock()
+	return len(op.orphans)
+}
+
+// expireLocked removes expired orphans; the caller must hold the lock
+func (op *OrphanPool) expireLocked() int {
+	now := op.clock.Now()
+	removed := 0
+	for txHash, orphan := range op.orphans {
+		if now.Sub(orphan.AddedTime) > op.expiry {
+			op.removeLocked(txHash)
+			removed++
+		}
+	}
+	return removed
+}
+
+// removeLocked deletes an orphan and its indexes; the caller must hold the lock
+func (op *OrphanPool) removeLocked(txHash string) {
+	orphan, exists := op.orphans[txHash]
+	if !exists {
+		return
+	}
+
+	for _, key := range orphan.Missing {
+		delete(op.byOutpoint[key], txHash)
+		if len(op.byOutpoint[key]) == 0 {
+			delete(op.byOutpoint, key)
+		}
+	}
+
+	op.perPeer[orphan.Peer]--
+	if op.perPeer[orphan.Peer] <= 0 {
+		delete(op.perPeer, orphan.Peer)
+	}
+
+	delete(op.orphans, txHash)
+}
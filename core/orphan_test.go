@@ -0,0 +1,77 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOrphanPoolLimits(t *testing.T) {
+	pool := NewOrphanPool(2, 1, time.Minute)
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	pool.SetClock(clock)
+
+	parent := pendingTx("funding", 0, 5)
+	child := pendingTx(parent.TxHash, 0, 4)
+	if err := pool.AddOrphan(child, "peer1", []string{outpointKey(parent.TxHash, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.AddOrphan(pendingTx("other", 0, 1), "peer1", []string{"other:0"}); err == nil {
+		t.Fatal("peer exceeded its orphan limit")
+	}
+
+	children := pool.TakeChildren(parent)
+	if len(children) != 1 || children[0].Tx != child || pool.Size() != 0 {
+		t.Fatalf("took %d children, %d orphans left", len(children), pool.Size())
+	}
+
+	// Large transactions are never held as orphans
+	large := pendingTx("funding", 1, 5)
+	large.Inputs[0].Signature = make([]byte, MaxOrphanTxSize)
+	large.TxHash = large.CalculateHash()
+	if err := pool.AddOrphan(large, "peer2", []string{"funding:1"}); err == nil {
+		t.Fatal("oversized orphan accepted")
+	}
+
+	if err := pool.AddOrphan(child, "peer2", []string{outpointKey(parent.TxHash, 0)}); err != nil {
+		t.Fatal(err)
+	}
+	clock.now = clock.now.Add(2 * time.Minute)
+	if n := pool.ExpireOrphans(); n != 1 || pool.HasOrphan(child.TxHash) {
+		t.Fatalf("expired %d orphans", n)
+	}
+}
+
+func TestRelayedOrphanAcceptedWithParent(t *testing.T) {
+	bc := newTestChain(t, "")
+	blocks := generate(t, bc, 101, "miner")
+
+	coinbase := blocks[0].Transactions[0]
+	value := coinbase.Outputs[0].Value
+	parent := spend(coinbase, []uint32{0}, "alice", value/2, value/2-10000)
+	child := spend(parent, []uint32{0}, "bob", value/2-10000)
+	sibling := spend(parent, []uint32{1}, "carol", value/2-20000)
+
+	if err := bc.ProcessTransaction(child, "peer1"); err != nil {
+		t.Fatal(err)
+	}
+	if !bc.Orphans.HasOrphan(child.TxHash) {
+		t.Fatal("relayed child not held as orphan")
+	}
+
+	// A local spend of unknown outputs is an error, not an orphan
+	err := bc.AddPendingTransaction(sibling)
+	if err == nil || !strings.Contains(err.Error(), "unknown outputs") {
+		t.Fatalf("local spend of unknown outputs returned %v", err)
+	}
+	if bc.Orphans.HasOrphan(sibling.TxHash) {
+		t.Fatal("local transaction held as orphan")
+	}
+
+	if err := bc.AddPendingTransaction(parent); err != nil {
+		t.Fatal(err)
+	}
+	if !bc.PendingTransactions.HasTransaction(child.TxHash) || bc.Orphans.Size() != 0 {
+		t.Fatal("orphan not accepted once its parent arrived")
+	}
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"fmt"
+)
+
+// DisconnectTip removes the most recent block from the chain and returns its
+// transactions to the mempool
+func (bc *Blockchain) DisconnectTip() (*Block, error) {
+	bc.mutex.Lock()
+	defer bc.mutex.Unlock()
+	return bc.disconnectTip()
+}
+
+// disconnectTip removes the tip block; the caller must hold the write lock
+func (bc *Blockchain) disconnectTip() (*Block, error) {
+	if len(bc.Blocks) <= 1 {
+		return nil, fmt.Errorf("cannot disconnect genesis block")
+	}
+
+	tip := bc.Blocks[len(bc.Blocks)-1]
+	undo := bc.undo[len(bc.undo)-1]
+
+	if err := bc.Chain.DeleteBlock(tip.Height); err != nil {
+		return nil, fmt.Errorf("failed to delete block #%d: %v", tip.Height, err)
+	}
+
+	// Remove the tip's outputs and restore the outputs it spent
+	disconnectBlock(bc.UTXOSet, tip, undo)
+
+	bc.Blocks = bc.Blocks[:len(bc.Blocks)-1]
+	bc.BlockTimestamps = bc.BlockTimestamps[:len(bc.BlockTimestamps)-1]
+	bc.undo = bc.undo[:len(bc.undo)-1]
+	bc.forgetDeploymentStates(tip.Height)
+
+	// Go back to the difficulty the chain expected of the tip, not the one
+	// its miner claimed in the header
+	bc.Difficulty = undo.difficulty
+
+	// Return transactions to the mempool; coinbase outputs no longer exist,
+	// so anything spending them, or a tx that can't be re-added, is dropped
+	readded := 0
+	for _, tx := range tip.Transactions {
+		if tx.IsCoinbase() {
+			bc.PendingTransactions.RemoveDescendants(tx)
+			continue
+		}
+
+		if err := bc.acceptToMempool(tx); err != nil {
+			bc.PendingTransactions.RemoveDescendants(tx)
+			continue
+		}
+		bc.processOrphans(tx)
+		readded++
+	}
+
+	// Coinbase, vesting and lock time heights are now one block further away,
+	// so drop pending transactions no longer allowed in the next block
+	nextHeight := uint64(len(bc.Blocks))
+	for _, entry := range bc.PendingTransactions.Snapshot() {
+		tx := entry.Tx
+		if bc.checkContext(tx, bc.UTXOSet, nextHeight, true) != nil {
+			bc.PendingTransactions.RemoveTransaction(tx.TxHash)
+			bc.PendingTransactions.RemoveDescendants(tx)
+		}
+	}
+
+	fmt.Printf("[Blockchain] Block #%d disconnected: %s, re-added %d txs to mempool\n",
+		tip.Height, tip.BlockHash[:16], readded)
+
+	return tip, nil
+}
+
+// Reorganize replaces every block above forkHeight with newBlocks. If any new
+// block fails to connect, the original chain is restored.
+func (bc *Blockchain) Reorganize(forkHeight uint64, newBlocks []*Block) error {
+	bc.mutex.Lock()
+	defer bc.mutex.Unlock()
+
+	if forkHeight >= uint64(len(bc.Blocks)) {
+		return fmt.Errorf("fork height %d is above chain tip", forkHeight)
+	}
+
+	var disconnected []*Block
+	for uint64(len(bc.Blocks))-1 > forkHeight {
+		block, err := bc.disconnectTip()
+		if err != nil {
+			return err
+		}
+		disconnected = append(disconnected, block)
+	}
+
+	for i, block := range newBlocks {
+		if err := bc.addBlock(block); err != nil {
+			// Roll back to the original chain
+			if rollbackErr := bc.rollbackReorganize(i, disconnected); rollbackErr != nil {
+				return fmt.Errorf("reorganization failed at block #%d: %v; rollback failed: %v",
+					block.Height, err, rollbackErr)
+			}
+			return fmt.Errorf("reorganization failed at block #%d: %v", block.Height, err)
+		}
+	}
+
+	fmt.Printf("[Blockchain] Reorganized at height %d: disconnected %d blocks, connected %d\n",
+		forkHeight, len(disconnected), len(newBlocks))
+
+	return nil
+}
+
+// rollbackReorganize disconnects the connected new blocks and reconnects the
+// disconnected ones, newest last, stopping at the first failure
+func (bc *Blockchain) rollbackReorganize(connected int, disconnected []*Block) error {
+	for j := 0; j < connected; j++ {
+		if _, err := bc.disconnectTip(); err != nil {
+			return err
+		}
+	}
+	for k := len(disconnected) - 1; k >= 0; k-- {
+		if err := bc.addBlock(disconnected[k]); err != nil {
+			return fmt.Errorf("failed to reconnect block #%d: %v", disconnected[k].Height, err)
+		}
+	}
+	return nil
+}
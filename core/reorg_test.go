@@ -0,0 +1,106 @@
+package core
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// replayUTXOs builds the UTXO set of the current chain from scratch
+func replayUTXOs(bc *Blockchain) *UTXOSet {
+	utxos := NewUTXOSet()
+	for _, block := range bc.Blocks {
+		connectBlock(utxos, block)
+	}
+	return utxos
+}
+
+func TestDisconnectTipRestoresSpentOutputs(t *testing.T) {
+	bc := newTestChain(t, "")
+	blocks := generate(t, bc, 101, "miner")
+	coinbase := blocks[0].Transactions[0]
+	parent, child := connectSpendingBlock(t, bc, coinbase)
+	generate(t, bc, 2, "miner")
+
+	for i := 0; i < 3; i++ {
+		if _, err := bc.DisconnectTip(); err != nil {
+			t.Fatal(err)
+		}
+		requireSameUTXOs(t, bc.UTXOSet, replayUTXOs(bc))
+	}
+
+	if bc.UTXOSet.FindUTXO(coinbase.TxHash, 0) == nil {
+		t.Fatal("coinbase output spent by the disconnected block not restored")
+	}
+	if bc.UTXOSet.FindUTXO(parent.TxHash, 1) != nil {
+		t.Fatal("output of a disconnected transaction still unspent")
+	}
+	if !bc.PendingTransactions.HasTransaction(parent.TxHash) || !bc.PendingTransactions.HasTransaction(child.TxHash) {
+		t.Fatal("disconnected transactions not returned to the mempool")
+	}
+}
+
+func TestReorganizeMatchesReplay(t *testing.T) {
+	bc := newTestChain(t, "")
+	blocks := generate(t, bc, 101, "miner")
+	connectSpendingBlock(t, bc, blocks[0].Transactions[0])
+	tip := bc.Blocks[len(bc.Blocks)-1]
+	before := replayUTXOs(bc)
+
+	// Replace the tip with the same block: the UTXO set must end up unchanged
+	if err := bc.Reorganize(tip.Height-1, []*Block{tip}); err != nil {
+		t.Fatal(err)
+	}
+	requireSameUTXOs(t, bc.UTXOSet, before)
+
+	// A failing reorganization restores the original chain
+	bad := *tip
+	bad.PrevBlockHash = "unknown"
+	if err := bc.Reorganize(tip.Height-1, []*Block{&bad}); err == nil {
+		t.Fatal("reorganization to an invalid block succeeded")
+	}
+	if bc.Blocks[len(bc.Blocks)-1] != tip {
+		t.Fatal("original tip not restored")
+	}
+	requireSameUTXOs(t, bc.UTXOSet, before)
+}
+
+func TestDisconnectTipRestoresExpectedDifficulty(t *testing.T) {
+	// Before the difficulty check a miner may claim any header difficulty
+	bc := newTestChain(t, "difficulty_check=1000")
+	expected := bc.Difficulty
+
+	block, err := bc.NewBlockTemplate("miner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Difficulty = expected + 1
+	if err := bc.AddBlock(solve(t, block)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := bc.DisconnectTip(); err != nil {
+		t.Fatal(err)
+	}
+	if bc.Difficulty != expected {
+		t.Fatalf("difficulty %d after disconnect, expected %d", bc.Difficulty, expected)
+	}
+}
+
+func TestReorganizeReportsFailedRollback(t *testing.T) {
+	bc := newTestChain(t, "")
+	clock := &fakeClock{now: time.Now()}
+	bc.SetTimeSource(NewMedianTimeSource(clock, DefaultMaxTimeOffset))
+	generate(t, bc, 2, "miner")
+	tip := bc.Blocks[len(bc.Blocks)-1]
+
+	// Once network time falls behind, the old tip is too far in the future
+	// to be reconnected
+	clock.now = clock.now.Add(-2 * time.Duration(bc.GenesisConfig.MaxFutureDrift) * time.Second)
+	bad := *tip
+	bad.PrevBlockHash = "unknown"
+	err := bc.Reorganize(tip.Height-1, []*Block{&bad})
+	if err == nil || !strings.Contains(err.Error(), "rollback failed") {
+		t.Fatalf("failed rollback not reported: %v", err)
+	}
+}
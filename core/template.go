@@ -0,0 +1,131 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// NewBlockTemplate assembles an unsolved block on top of the current tip. It
+// picks mempool transactions by fee rate, keeping parents ahead of children,
+// builds the coinbase including any required treasury outputs, and sets the
+// version bits of deployments that are collecting signals.
+func (bc *Blockchain) NewBlockTemplate(minerAddress string) (*Block, error) {
+	bc.mutex.RLock()
+	defer bc.mutex.RUnlock()
+
+	if len(bc.Blocks) == 0 {
+		return nil, fmt.Errorf("chain has no genesis block")
+	}
+
+	tip := bc.Blocks[len(bc.Blocks)-1]
+	height := tip.Height + 1
+
+	subsidy := bc.RewardCalculator.GetBlockReward(height)
+
+	// Reserve room for the header and coinbase. Amounts are fixed width, but
+	// the miner output is left out when it would be empty, so measure with a
+	// placeholder fee to keep room for it once real fees are collected.
+	coinbase := NewCoinbaseTransaction(height, 0, bc.GenesisConfig.CoinbaseOutputs(height, subsidy, 1, minerAddress))
+	baseSize := NewBlock(tip.BlockHash, []*Transaction{coinbase}, bc.Difficulty, height, minerAddress).Size()
+
+	maxBytes, maxTxs := math.MaxInt, math.MaxInt
+	if limit := int(bc.GenesisConfig.MaxBlockSize); limit > 0 {
+		maxBytes = limit - baseSize
+	}
+	if limit := int(bc.GenesisConfig.MaxTxPerBlock); limit > 0 {
+		maxTxs = limit - 1
+	}
+
+	txs, fees := bc.selectTransactions(height, maxBytes, maxTxs)
+	coinbase = NewCoinbaseTransaction(height, 0, bc.GenesisConfig.CoinbaseOutputs(height, subsidy, fees, minerAddress))
+	coinbase.Timestamp = bc.adjustedTime()
+	coinbase.TxHash = coinbase.CalculateHash()
+
+	transactions := append([]*Transaction{coinbase}, txs...)
+	block := NewBlock(tip.BlockHash, transactions, bc.Difficulty, height, minerAddress)
+
+	// Signal for deployments that are collecting miner support, and stamp the
+	// block with network time, kept ahead of median-time-past when blocks come
+	// faster than a second
+	block.Version = bc.nextBlockVersion(height)
+	block.Timestamp = bc.adjustedTime()
+	if mtp := bc.medianTimePast(height); block.Timestamp <= mtp {
+		block.Timestamp = mtp + 1
+	}
+	block.BlockHash = block.CalculateHash()
+
+	return block, nil
+}
+
+// GenerateBlocks mines n blocks paying minerAddress and connects them. It is meant
+// for regtest, where the trivial difficulty makes mining instant.
+func (bc *Blockchain) GenerateBlocks(n int, minerAddress string) ([]*Block, error) {
+	blocks := make([]*Block, 0, n)
+	for i := 0; i < n; i++ {
+		block, err := bc.NewBlockTemplate(minerAddress)
+		if err != nil {
+			return blocks, err
+		}
+
+		if err := block.Mine(); err != nil {
+			return blocks, err
+		}
+
+		if err := bc.AddBlock(block); err != nil {
+			return blocks, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
+// selectTransactions picks valid mempool transactions, highest fee rate first,
+// within maxBytes and maxTxs, and returns them in an order where every parent
+// precedes its children
+func (bc *Blockchain) selectTransactions(height uint64, maxBytes, maxTxs int) ([]*Transaction, uint64) {
+	entries := bc.PendingTransactions.Snapshot()
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].FeeRate() > entries[j].FeeRate()
+	})
+
+	view := newUTXOOverlay(bc.UTXOSet)
+	var selected []*Transaction
+	fees := uint64(0)
+
+	// Keep passing over the remaining entries until no more become valid;
+	// a child skipped in one pass is picked up once its parent is included
+	for progress := true; progress && len(selected) < maxTxs; {
+		progress = false
+		remaining := entries[:0]
+
+		for _, entry := range entries {
+			if len(selected) >= maxTxs {
+				break
+			}
+
+			// Space only shrinks, so a transaction that doesn't fit now never will
+			if entry.Size > maxBytes {
+				continue
+			}
+
+			tx := entry.Tx
+			if !tx.HasAllInputs(view) || !tx.Validate(view, bc.GenesisConfig.Upgrades, height) ||
+				bc.checkContext(tx, view, height, true) != nil {
+				remaining = append(remaining, entry)
+				continue
+			}
+
+			fees += tx.GetTotalInput(view) - tx.GetTotalOutput()
+			connectTransaction(view, tx, height)
+
+			selected = append(selected, tx)
+			maxBytes -= entry.Size
+			progress = true
+		}
+
+		entries = remaining
+	}
+
+	return selected, fees
+}
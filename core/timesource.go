@@ -0,0 +1,101 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// maxTimeSamples caps how many peers contribute clock offsets
+	maxTimeSamples = 200
+	// minTimeSamples is how many peer samples are needed before adjusting
+	minTimeSamples = 5
+	// DefaultMaxTimeOffset bounds how far peers can move our clock
+	DefaultMaxTimeOffset = 70 * time.Minute
+	// timeSkewWarning is the offset above which the local clock is reported as wrong
+	timeSkewWarning = 5 * time.Minute
+)
+
+// TimeSource supplies network-adjusted time: the local clock corrected by
+// the offsets peers report
+type TimeSource interface {
+	Clock
+	AddSample(peer string, peerTime time.Time)
+	Offset() time.Duration
+}
+
+// MedianTimeSource adjusts a local clock by the median offset of its peers
+type MedianTimeSource struct {
+	mutex     sync.RWMutex
+	clock     Clock
+	samples   map[string]time.Duration // peer -> peer clock minus local clock
+	offset    time.Duration
+	maxOffset time.Duration
+	warned    bool
+}
+
+// NewMedianTimeSource creates a time source on top of clock, ignoring median
+// offsets larger than maxOffset
+func NewMedianTimeSource(clock Clock, maxOffset time.Duration) *MedianTimeSource {
+	return &MedianTimeSource{
+		clock:     clock,
+		samples:   make(map[string]time.Duration),
+		maxOffset: maxOffset,
+	}
+}
+
+// Now returns network-adjusted time
+func (mts *MedianTimeSource) Now() time.Time {
+	mts.mutex.RLock()
+	defer mts.mutex.RUnlock()
+	return mts.clock.Now().Add(mts.offset)
+}
+
+// Offset returns the adjustment currently applied to the local clock
+func (mts *MedianTimeSource) Offset() time.Duration {
+	mts.mutex.RLock()
+	defer mts.mutex.RUnlock()
+	return mts.offset
+}
+
+// AddSample records the time a peer reported. Each peer counts once, and
+// only the first maxTimeSamples peers are used.
+func (mts *MedianTimeSource) AddSample(peer string, peerTime time.Time) {
+	mts.mutex.Lock()
+	defer mts.mutex.Unlock()
+
+	if _, exists := mts.samples[peer]; exists || len(mts.samples) >= maxTimeSamples {
+		return
+	}
+	mts.samples[peer] = peerTime.Sub(mts.clock.Now())
+
+	if len(mts.samples) < minTimeSamples {
+		return
+	}
+
+	offsets := make([]time.Duration, 0, len(mts.samples))
+	for _, offset := range mts.samples {
+		offsets = append(offsets, offset)
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	median := offsets[len(offsets)/2]
+
+	// Outvoting our clock by a large margin is more likely an attack or a
+	// misconfigured network than a wrong local clock, so don't follow it
+	if median > mts.maxOffset || median < -mts.maxOffset {
+		mts.offset = 0
+		if !mts.warned {
+			mts.warned = true
+			fmt.Printf("[TimeSource] WARNING: peers report a clock offset of %v, beyond the %v limit. "+
+				"Check that your computer's date and time are correct.\n", median, mts.maxOffset)
+		}
+		return
+	}
+
+	mts.offset = median
+	if median > timeSkewWarning || median < -timeSkewWarning {
+		fmt.Printf("[TimeSource] Local clock is %v off network time, adjusting\n", median)
+	}
+}
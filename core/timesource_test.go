@@ -0,0 +1,71 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// addPeerSamples reports n peers whose clocks are offset from clock
+func addPeerSamples(ts TimeSource, clock Clock, n int, offset time.Duration) {
+	for i := 0; i < n; i++ {
+		ts.AddSample(fmt.Sprintf("peer%d", i), clock.Now().Add(offset))
+	}
+}
+
+func TestMedianTimeSourceOffset(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	ts := NewMedianTimeSource(clock, DefaultMaxTimeOffset)
+
+	addPeerSamples(ts, clock, minTimeSamples-1, 10*time.Minute)
+	if ts.Offset() != 0 {
+		t.Fatalf("adjusted with only %d samples", minTimeSamples-1)
+	}
+
+	// A peer reporting again doesn't count twice
+	ts.AddSample("peer0", clock.Now().Add(time.Hour))
+	if ts.Offset() != 0 {
+		t.Fatal("repeated peer sample counted")
+	}
+
+	ts.AddSample("late", clock.Now().Add(-time.Minute))
+	if ts.Offset() != 10*time.Minute {
+		t.Fatalf("offset %v, want the median 10m", ts.Offset())
+	}
+	if got, want := ts.Now(), clock.Now().Add(10*time.Minute); !got.Equal(want) {
+		t.Fatalf("adjusted time %v, want %v", got, want)
+	}
+}
+
+func TestMedianTimeSourceIgnoresLargeOffsets(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(1700000000, 0)}
+	ts := NewMedianTimeSource(clock, DefaultMaxTimeOffset)
+
+	addPeerSamples(ts, clock, minTimeSamples, DefaultMaxTimeOffset+time.Minute)
+	if ts.Offset() != 0 {
+		t.Fatalf("followed peers %v ahead", ts.Offset())
+	}
+}
+
+func TestBlockDriftUsesNetworkTime(t *testing.T) {
+	bc := newTestChain(t, "")
+	clock := &fakeClock{now: time.Now()}
+	ts := NewMedianTimeSource(clock, DefaultMaxTimeOffset)
+	bc.SetTimeSource(ts)
+
+	block, err := bc.NewBlockTemplate("miner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	drift := time.Duration(bc.GenesisConfig.MaxFutureDrift) * time.Second
+	block.Timestamp = clock.Now().Add(drift + 30*time.Minute).Unix()
+	if err := bc.AddBlock(solve(t, block)); err == nil {
+		t.Fatal("block beyond the future drift accepted")
+	}
+
+	// Peers an hour ahead move the limit with them
+	addPeerSamples(ts, clock, minTimeSamples, time.Hour)
+	if err := bc.AddBlock(solve(t, block)); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"fmt"
+)
+
+// TreasuryPeriod names the treasury addresses in force from StartHeight on
+type TreasuryPeriod struct {
+	StartHeight uint64
+	Addresses   []string
+}
+
+// TreasuryConfig requires a share of each block subsidy to go to the treasury
+type TreasuryConfig struct {
+	ShareBasisPoints uint64           // Share of the subsidy in 1/10000ths
+	Periods          []TreasuryPeriod // Address rotation, ordered by StartHeight
+}
+
+// Validate checks that the treasury rule is well formed
+func (tc *TreasuryConfig) Validate() error {
+	if tc.ShareBasisPoints > 10000 {
+		return fmt.Errorf("treasury share exceeds 100%%: %d basis points", tc.ShareBasisPoints)
+	}
+
+	for i, period := range tc.Periods {
+		if i > 0 && period.StartHeight <= tc.Periods[i-1].StartHeight {
+			return fmt.Errorf("treasury periods must be ordered by height at period %d", i)
+		}
+		if len(period.Addresses) == 0 {
+			return fmt.Errorf("treasury period %d has no addresses", i)
+		}
+	}
+
+	return nil
+}
+
+// AddressesAt returns the treasury addresses in force at a height
+func (tc *TreasuryConfig) AddressesAt(height uint64) []string {
+	var addresses []string
+	for _, period := range tc.Periods {
+		if period.StartHeight > height {
+			break
+		}
+		addresses = period.Addresses
+	}
+	return addresses
+}
+
+// RequiredOutputs returns the treasury outputs a coinbase at height must include.
+// The share is split evenly between the addresses; any remainder goes to the first.
+func (tc *TreasuryConfig) RequiredOutputs(height, subsidy uint64) []Output {
+	addresses := tc.AddressesAt(height)
+	if len(addresses) == 0 || tc.ShareBasisPoints == 0 {
+		return nil
+	}
+
+	share := subsidy / 10000 * tc.ShareBasisPoints
+	share += subsidy % 10000 * tc.ShareBasisPoints / 10000
+	if share == 0 {
+		return nil
+	}
+
+	each := share / uint64(len(addresses))
+	outputs := make([]Output, 0, len(addresses))
+	for i, address := range addresses {
+		value := each
+		if i == 0 {
+			value += share % uint64(len(addresses))
+		}
+		if value > 0 {
+			outputs = append(outputs, Output{Value: value, Address: address, LockScript: "OP_CHECKSIG"})
+		}
+	}
+
+	return outputs
+}
+
+// checkTreasuryOutputs verifies the coinbase pays every required treasury output
+func (gc *GenesisConfig) checkTreasuryOutputs(coinbase *Transaction, height, subsidy uint64) error {
+	if gc.Treasury == nil {
+		return nil
+	}
+
+	paid := make(map[string]uint64)
+	for _, output := range coinbase.Outputs {
+		paid[output.Address] += output.Value
+	}
+
+	for _, required := range gc.Treasury.RequiredOutputs(height, subsidy) {
+		if paid[required.Address] < required.Value {
+			return fmt.Errorf("coinbase pays treasury %s %d, requires %d",
+				required.Address, paid[required.Address], required.Value)
+		}
+		paid[required.Address] -= required.Value
+	}
+
+	return nil
+}
+
+// CoinbaseOutputs builds the coinbase outputs for a block at height: the treasury
+// share of the subsidy, and the rest of the subsidy plus fees to the miner. The
+// miner output is left out when there is nothing left to pay, as zero-value
+// outputs are invalid.
+func (gc *GenesisConfig) CoinbaseOutputs(height, subsidy, fees uint64, minerAddress string) []Output {
+	var outputs []Output
+	minerValue := subsidy + fees
+
+	if gc.Treasury != nil {
+		for _, output := range gc.Treasury.RequiredOutputs(height, subsidy) {
+			outputs = append(outputs, output)
+			minerValue -= output.Value
+		}
+	}
+
+	if minerValue == 0 {
+		return outputs
+	}
+	return append([]Output{{Value: minerValue, Address: minerAddress, LockScript: "OP_CHECKSIG"}}, outputs...)
+}
@@ -0,0 +1,52 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestCoinbaseOutputsOmitZeroMinerOutput(t *testing.T) {
+	gc := GetRegtestGenesis()
+	gc.Treasury = &TreasuryConfig{
+		ShareBasisPoints: 10000,
+		Periods:          []TreasuryPeriod{{StartHeight: 0, Addresses: []string{"treasury"}}},
+	}
+
+	outputs := gc.CoinbaseOutputs(1, 1000, 0, "miner")
+	if len(outputs) != 1 || outputs[0].Address != "treasury" || outputs[0].Value != 1000 {
+		t.Fatalf("full treasury share gave %+v", outputs)
+	}
+
+	outputs = gc.CoinbaseOutputs(1, 1000, 5, "miner")
+	if len(outputs) != 2 || outputs[0].Address != "miner" || outputs[0].Value != 5 {
+		t.Fatalf("fees with full treasury share gave %+v", outputs)
+	}
+
+	if outputs := gc.CoinbaseOutputs(1, 0, 0, "miner"); len(outputs) != 0 {
+		t.Fatalf("nothing to pay gave %+v", outputs)
+	}
+}
+
+func TestGenerateBlocksWithNothingForMiner(t *testing.T) {
+	params := RegtestParams()
+	params.Genesis.Treasury = &TreasuryConfig{
+		ShareBasisPoints: 10000,
+		Periods:          []TreasuryPeriod{{StartHeight: 0, Addresses: []string{"treasury"}}},
+	}
+	// The subsidy runs out after a few blocks
+	params.Genesis.Emission.InitialReward = 4
+	params.Genesis.Emission.HalvingInterval = 2
+
+	bc, err := NewBlockchain(newMemStorage(), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Close()
+
+	generate(t, bc, 10, "miner")
+	if balance := bc.UTXOSet.GetBalance("miner"); balance != 0 {
+		t.Fatalf("miner received %d", balance)
+	}
+	if bc.UTXOSet.GetBalance("treasury") == 0 {
+		t.Fatal("treasury received nothing")
+	}
+}
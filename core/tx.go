@@ -1,10 +1,29 @@
 package core
 
 import (
+	"bytes"
 	"crypto/sha256"
+	"encoding/binary"
 	"encoding/hex"
 	"fmt"
 	"time"
+
+	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/consensus"
+)
+
+// LockTime values below this are block heights, at or above it unix timestamps
+const LockTimeThreshold = 500000000
+
+// MaxSequence marks an input as final; a transaction whose inputs are all
+// final ignores its LockTime
+const MaxSequence = uint32(0xFFFFFFFF)
+
+// Relative lock time encoding of Input.Sequence, honoured from transaction version 2
+const (
+	SequenceLockTimeDisableFlag = uint32(1 << 31) // Input has no relative lock
+	SequenceLockTimeTypeFlag    = uint32(1 << 22) // Lock counts seconds rather than blocks
+	SequenceLockTimeMask        = uint32(0x0000FFFF)
+	SequenceLockTimeGranularity = 9 // Time locks count units of 512 seconds
 )
 
 // Input represents a transaction input (previous output reference)
@@ -13,6 +32,12 @@ type Input struct {
 	OutIndex  uint32 // Index of output in previous transaction
 	Signature []byte // Signature from spender
 	PublicKey []byte // Public key of spender
+	Sequence  uint32 // MaxSequence opts the input out of LockTime
+
+	// Coinbase inputs only: the block height and a miner-chosen extra nonce,
+	// which make every coinbase hash unique
+	CoinbaseHeight uint64
+	ExtraNonce     uint64
 }
 
 // Output represents a transaction output
@@ -20,6 +45,7 @@ type Output struct {
 	Value      uint64 // Amount in satoshis (smallest unit)
 	Address    string // Recipient address
 	LockScript string // Locking script (simplified: "OP_CHECKSIG")
+	UnlockHeight uint64 // Can't be spent before this height (0 = no lock)
 }
 
 // Transaction represents a blockchain transaction
@@ -29,6 +55,7 @@ type Transaction struct {
 	Outputs  []Output
 	LockTime int64
 	Timestamp int64
+	ExpiryHeight uint64 // Can't be mined above this height (0 = never expires)
 	TxHash   string // Calculated hash
 }
 
@@ -46,6 +73,11 @@ func NewTransaction(inputs []Input, outputs []Output) *Transaction {
 	return tx
 }
 
+// NewCoinbaseTransaction creates the reward transaction of the block at height
+func NewCoinbaseTransaction(height, extraNonce uint64, outputs []Output) *Transaction {
+	return NewTransaction([]Input{{TxHash: "", OutIndex: 0, CoinbaseHeight: height, ExtraNonce: extraNonce}}, outputs)
+}
+
 // CalculateHash computes the transaction hash
 func (t *Transaction) CalculateHash() string {
 	// Serialize transaction data
@@ -59,6 +91,9 @@ func (t *Transaction) CalculateHash() string {
 			t.Timestamp,
 		),
 	)
+	if t.ExpiryHeight > 0 {
+		data = append(data, fmt.Sprintf("^%d", t.ExpiryHeight)...)
+	}
 
 	hash := sha256.Sum256(data)
 	hash = sha256.Sum256(hash[:])
@@ -70,6 +105,12 @@ func (t *Transaction) serializeInputs() string {
 	result := ""
 	for _, input := range t.Inputs {
 		result += input.TxHash + fmt.Sprintf("%d", input.OutIndex)
+		if input.CoinbaseHeight > 0 || input.ExtraNonce > 0 {
+			result += fmt.Sprintf("#%d:%d", input.CoinbaseHeight, input.ExtraNonce)
+		}
+		if input.Sequence != 0 {
+			result += fmt.Sprintf("~%d", input.Sequence)
+		}
 	}
 	return result
 }
@@ -79,17 +120,63 @@ func (t *Transaction) serializeOutputs() string {
 	result := ""
 	for _, output := range t.Outputs {
 		result += output.Address + fmt.Sprintf("%d", output.Value)
+		if output.UnlockHeight > 0 {
+			result += fmt.Sprintf("@%d", output.UnlockHeight)
+		}
 	}
 	return result
 }
 
+// Serialize encodes the transaction in its canonical binary form
+func (t *Transaction) Serialize() []byte {
+	var buf bytes.Buffer
+
+	binary.Write(&buf, binary.LittleEndian, t.Version)
+
+	binary.Write(&buf, binary.LittleEndian, uint32(len(t.Inputs)))
+	for _, input := range t.Inputs {
+		writeBytes(&buf, []byte(input.TxHash))
+		binary.Write(&buf, binary.LittleEndian, input.OutIndex)
+		writeBytes(&buf, input.Signature)
+		writeBytes(&buf, input.PublicKey)
+		binary.Write(&buf, binary.LittleEndian, input.CoinbaseHeight)
+		binary.Write(&buf, binary.LittleEndian, input.ExtraNonce)
+		binary.Write(&buf, binary.LittleEndian, input.Sequence)
+	}
+
+	binary.Write(&buf, binary.LittleEndian, uint32(len(t.Outputs)))
+	for _, output := range t.Outputs {
+		binary.Write(&buf, binary.LittleEndian, output.Value)
+		writeBytes(&buf, []byte(output.Address))
+		writeBytes(&buf, []byte(output.LockScript))
+		binary.Write(&buf, binary.LittleEndian, output.UnlockHeight)
+	}
+
+	binary.Write(&buf, binary.LittleEndian, t.LockTime)
+	binary.Write(&buf, binary.LittleEndian, t.Timestamp)
+	binary.Write(&buf, binary.LittleEndian, t.ExpiryHeight)
+
+	return buf.Bytes()
+}
+
+// Size returns the canonical serialized size in bytes
+func (t *Transaction) Size() int {
+	return len(t.Serialize())
+}
+
+// writeBytes writes a length-prefixed byte slice
+func writeBytes(buf *bytes.Buffer, data []byte) {
+	binary.Write(buf, binary.LittleEndian, uint32(len(data)))
+	buf.Write(data)
+}
+
 // IsCoinbase checks if transaction is a block reward (coinbase)
 func (t *Transaction) IsCoinbase() bool {
 	return len(t.Inputs) == 1 && t.Inputs[0].TxHash == "" && t.Inputs[0].OutIndex == 0
 }
 
 // GetTotalInput calculates total input value
-func (t *Transaction) GetTotalInput(utxoSet *UTXOSet) uint64 {
+func (t *Transaction) GetTotalInput(utxoSet UTXOView) uint64 {
 	total := uint64(0)
 	for _, input := range t.Inputs {
 		utxo := utxoSet.FindUTXO(input.TxHash, input.OutIndex)
@@ -109,15 +196,49 @@ func (t *Transaction) GetTotalOutput() uint64 {
 	return total
 }
 
-// Validate performs basic transaction validation
-func (t *Transaction) Validate(utxoSet *UTXOSet) bool {
-	if len(t.Inputs) == 0 || len(t.Outputs) == 0 {
+// checkedTotalOutput calculates total output value, reporting false if it
+// overflows a uint64
+func (t *Transaction) checkedTotalOutput() (uint64, bool) {
+	total := uint64(0)
+	for _, output := range t.Outputs {
+		if total+output.Value < total {
+			return 0, false
+		}
+		total += output.Value
+	}
+	return total, true
+}
+
+// Validate performs basic transaction validation under the rules in force at height
+func (t *Transaction) Validate(utxoSet UTXOView, upgrades consensus.UpgradeSchedule, height uint64) bool {
+	// A coinbase has no outputs once the subsidy has run out in a block without fees
+	if len(t.Inputs) == 0 || (len(t.Outputs) == 0 && !t.IsCoinbase()) {
+		return false
+	}
+
+	// An outpoint can only be spent once, even within one transaction
+	spent := make(map[string]bool, len(t.Inputs))
+	for _, input := range t.Inputs {
+		key := outpointKey(input.TxHash, input.OutIndex)
+		if spent[key] {
+			return false
+		}
+		spent[key] = true
+	}
+
+	if upgrades.IsActive(consensus.UpgradeOutputChecks, height) && !t.outputsValid() {
+		return false
+	}
+
+	// An overflowing output total would wrap around to a small value, so it
+	// is rejected whatever the upgrade schedule
+	outputTotal, ok := t.checkedTotalOutput()
+	if !ok {
 		return false
 	}
 
 	if !t.IsCoinbase() {
 		inputTotal := t.GetTotalInput(utxoSet)
-		outputTotal := t.GetTotalOutput()
 
 		if inputTotal < outputTotal {
 			return false
@@ -126,3 +247,126 @@ func (t *Transaction) Validate(utxoSet *UTXOSet) bool {
 
 	return true
 }
+
+// IsFinal reports whether the transaction may be included in a block at
+// height whose median-time-past is blockTime. LockTime below LockTimeThreshold
+// is a height, otherwise a timestamp; the transaction is final once LockTime
+// has passed, or if every input has opted out with MaxSequence.
+func (t *Transaction) IsFinal(height uint64, blockTime int64) bool {
+	if t.LockTime <= 0 {
+		return true
+	}
+
+	limit := blockTime
+	if t.LockTime < LockTimeThreshold {
+		limit = int64(height)
+	}
+	if t.LockTime < limit {
+		return true
+	}
+
+	for _, input := range t.Inputs {
+		if input.Sequence != MaxSequence {
+			return false
+		}
+	}
+	return true
+}
+
+// IsExpired reports whether the transaction can no longer be mined at height
+func (t *Transaction) IsExpired(height uint64) bool {
+	return t.ExpiryHeight > 0 && height > t.ExpiryHeight
+}
+
+// CheckSequenceLocks verifies each input's relative lock has passed for a block
+// at height with median-time-past blockTime. A lock counts blocks or 512-second
+// units from the spent output's creation; timeAt returns the median-time-past
+// at which an output created at a height counts as confirmed. Transactions
+// below version 2 and inputs with the disable flag have no relative lock.
+func (t *Transaction) CheckSequenceLocks(utxoSet UTXOView, height uint64, blockTime int64, timeAt func(uint64) int64) error {
+	if t.Version < 2 || t.IsCoinbase() {
+		return nil
+	}
+
+	for _, input := range t.Inputs {
+		if input.Sequence&SequenceLockTimeDisableFlag != 0 {
+			continue
+		}
+		utxo := utxoSet.FindUTXO(input.TxHash, input.OutIndex)
+		if utxo == nil {
+			continue
+		}
+
+		// Unconfirmed outputs would confirm in the block being checked at the earliest
+		lock := uint64(input.Sequence & SequenceLockTimeMask)
+		if input.Sequence&SequenceLockTimeTypeFlag != 0 {
+			confirmedAt := blockTime
+			if utxo.Height != MempoolHeight {
+				confirmedAt = timeAt(utxo.Height)
+			}
+			if unlock := confirmedAt + int64(lock<<SequenceLockTimeGranularity); blockTime < unlock {
+				return fmt.Errorf("input %s:%d is time locked until %d", input.TxHash, input.OutIndex, unlock)
+			}
+		} else {
+			confirmedAt := height
+			if utxo.Height != MempoolHeight {
+				confirmedAt = utxo.Height
+			}
+			if unlock := confirmedAt + lock; height < unlock {
+				return fmt.Errorf("input %s:%d is locked until height %d", input.TxHash, input.OutIndex, unlock)
+			}
+		}
+	}
+	return nil
+}
+
+// outputsValid checks that every output carries value and the total fits in a uint64
+func (t *Transaction) outputsValid() bool {
+	total := uint64(0)
+	for _, output := range t.Outputs {
+		if output.Value == 0 || total+output.Value < total {
+			return false
+		}
+		total += output.Value
+	}
+	return true
+}
+
+// CheckUnlocked verifies no input spends an output before its unlock height
+func (t *Transaction) CheckUnlocked(utxoSet UTXOView, height uint64) error {
+	for _, input := range t.Inputs {
+		utxo := utxoSet.FindUTXO(input.TxHash, input.OutIndex)
+		if utxo != nil && utxo.UnlockHeight > height {
+			return fmt.Errorf("input %s:%d is locked until height %d",
+				input.TxHash, input.OutIndex, utxo.UnlockHeight)
+		}
+	}
+	return nil
+}
+
+// CheckCoinbaseMaturity verifies no input spends a coinbase output created
+// fewer than maturity blocks before height. Genesis can't be reorganized
+// away, so its outputs are mature from the start.
+func (t *Transaction) CheckCoinbaseMaturity(utxoSet UTXOView, height, maturity uint64) error {
+	for _, input := range t.Inputs {
+		utxo := utxoSet.FindUTXO(input.TxHash, input.OutIndex)
+		if utxo == nil || !utxo.IsCoinbase || utxo.Height == 0 {
+			continue
+		}
+		if height < utxo.Height+maturity {
+			return fmt.Errorf("input %s:%d spends a coinbase from height %d, matures at %d",
+				input.TxHash, input.OutIndex, utxo.Height, utxo.Height+maturity)
+		}
+	}
+	return nil
+}
+
+// HasAllInputs reports whether every input refers to an output in the view
+func (t *Transaction) HasAllInputs(utxoSet UTXOView) bool {
+	for _, input := range t.Inputs {
+		if utxoSet.FindUTXO(input.TxHash, input.OutIndex) == nil {
+			return false
+		}
+	}
+	return true
+}
@@ -12,6 +12,27 @@ type UTXO struct {
 	Value     uint64
 	Address   string
 	LockScript string
+	UnlockHeight uint64 // Can't be spent before this height (0 = no lock)
+	Height       uint64 // Height of the block that created it (MempoolHeight if unconfirmed)
+	IsCoinbase   bool   // Created by a coinbase transaction
+}
+
+// MempoolHeight is the creation height given to outputs of unconfirmed transactions
+const MempoolHeight = uint64(0x7FFFFFFF)
+
+// newUTXO creates the UTXO for output outIndex of tx, created at height
+func newUTXO(tx *Transaction, outIndex uint32, height uint64) *UTXO {
+	output := tx.Outputs[outIndex]
+	return &UTXO{
+		TxHash:       tx.TxHash,
+		OutIndex:     outIndex,
+		Value:        output.Value,
+		Address:      output.Address,
+		LockScript:   output.LockScript,
+		UnlockHeight: output.UnlockHeight,
+		Height:       height,
+		IsCoinbase:   tx.IsCoinbase(),
+	}
 }
 
 // UTXOKey creates a unique key for a UTXO
@@ -19,6 +40,16 @@ func (u *UTXO) Key() string {
 	return fmt.Sprintf("%s:%d", u.TxHash, u.OutIndex)
 }
 
+// outpointKey builds the map key for an output reference
+func outpointKey(txHash string, outIndex uint32) string {
+	return fmt.Sprintf("%s:%d", txHash, outIndex)
+}
+
+// UTXOView is anything transactions can be validated against
+type UTXOView interface {
+	FindUTXO(txHash string, outIndex uint32) *UTXO
+}
+
 // UTXOSet manages all unspent transaction outputs
 type UTXOSet struct {
 	mutex sync.RWMutex
@@ -96,3 +127,103 @@ func (us *UTXOSet) GetAll() []*UTXO {
 	}
 	return result
 }
+
+// utxoOverlay layers uncommitted outputs and spends on top of a base view
+type utxoOverlay struct {
+	base  UTXOView
+	added map[string]*UTXO
+	spent map[string]bool
+}
+
+// newUTXOOverlay creates an empty overlay on top of base
+func newUTXOOverlay(base UTXOView) *utxoOverlay {
+	return &utxoOverlay{
+		base:  base,
+		added: make(map[string]*UTXO),
+		spent: make(map[string]bool),
+	}
+}
+
+// FindUTXO looks up an output in the overlay first, then in the base view
+func (uo *utxoOverlay) FindUTXO(txHash string, outIndex uint32) *UTXO {
+	key := outpointKey(txHash, outIndex)
+	if uo.spent[key] {
+		return nil
+	}
+	if utxo, exists := uo.added[key]; exists {
+		return utxo
+	}
+	return uo.base.FindUTXO(txHash, outIndex)
+}
+
+// AddUTXO records an output created on top of the base view
+func (uo *utxoOverlay) AddUTXO(utxo *UTXO) {
+	key := utxo.Key()
+	delete(uo.spent, key)
+	uo.added[key] = utxo
+}
+
+// RemoveUTXO marks an output as spent in the overlay
+func (uo *utxoOverlay) RemoveUTXO(txHash string, outIndex uint32) {
+	key := outpointKey(txHash, outIndex)
+	delete(uo.added, key)
+	uo.spent[key] = true
+}
+
+// utxoWriter is a view that connecting transactions updates
+type utxoWriter interface {
+	UTXOView
+	AddUTXO(utxo *UTXO)
+	RemoveUTXO(txHash string, outIndex uint32)
+}
+
+// blockUndo holds what connecting a block changed, so it can be disconnected
+// without replaying the chain
+type blockUndo struct {
+	spent      [][]*UTXO // Outputs each transaction spent, in block order
+	difficulty uint32    // Difficulty expected of the block, restored on disconnect
+}
+
+// connectTransaction spends tx's inputs and adds its outputs, created at
+// height, to view, returning the spent outputs. Every path that applies
+// transactions goes through here, so outputs are always keyed by their
+// position in the transaction.
+func connectTransaction(view utxoWriter, tx *Transaction, height uint64) []*UTXO {
+	var spent []*UTXO
+	if !tx.IsCoinbase() {
+		for _, input := range tx.Inputs {
+			if utxo := view.FindUTXO(input.TxHash, input.OutIndex); utxo != nil {
+				spent = append(spent, utxo)
+			}
+			view.RemoveUTXO(input.TxHash, input.OutIndex)
+		}
+	}
+	for i := range tx.Outputs {
+		view.AddUTXO(newUTXO(tx, uint32(i), height))
+	}
+	return spent
+}
+
+// connectBlock applies a block's transactions to view in order and returns
+// the undo data for disconnecting it; the caller records the difficulty
+func connectBlock(view utxoWriter, block *Block) blockUndo {
+	undo := blockUndo{spent: make([][]*UTXO, len(block.Transactions))}
+	for i, tx := range block.Transactions {
+		undo.spent[i] = connectTransaction(view, tx, block.Height)
+	}
+	return undo
+}
+
+// disconnectBlock reverts connectBlock: transactions are undone in reverse
+// order, removing their outputs and restoring the outputs they spent
+func disconnectBlock(view utxoWriter, block *Block, undo blockUndo) {
+	for i := len(block.Transactions) - 1; i >= 0; i-- {
+		tx := block.Transactions[i]
+		for j := range tx.Outputs {
+			view.RemoveUTXO(tx.TxHash, uint32(j))
+		}
+		for _, utxo := range undo.spent[i] {
+			view.AddUTXO(utxo)
+		}
+	}
+}
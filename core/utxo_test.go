@@ -0,0 +1,76 @@
+package core
+
+import (
+	"testing"
+)
+
+// requireSameUTXOs fails unless both sets hold exactly the same outputs
+func requireSameUTXOs(t *testing.T, live, rebuilt *UTXOSet) {
+	t.Helper()
+
+	if live.Count() != rebuilt.Count() {
+		t.Fatalf("live set has %d outputs, rebuild has %d", live.Count(), rebuilt.Count())
+	}
+	for _, utxo := range rebuilt.GetAll() {
+		found := live.FindUTXO(utxo.TxHash, utxo.OutIndex)
+		if found == nil {
+			t.Fatalf("live set is missing %s", utxo.Key())
+		}
+		if *found != *utxo {
+			t.Fatalf("live %s is %+v, rebuild has %+v", utxo.Key(), *found, *utxo)
+		}
+	}
+}
+
+// connectSpendingBlock mines a block spending a mature coinbase through a
+// multi-output transaction and a child spending two of its outputs in the same block
+func connectSpendingBlock(t *testing.T, bc *Blockchain, coinbase *Transaction) (*Transaction, *Transaction) {
+	t.Helper()
+
+	value := coinbase.Outputs[0].Value
+	parent := spend(coinbase, []uint32{0}, "alice", value/4, value/4, value/4, value/4-1000)
+	child := spend(parent, []uint32{0, 2}, "bob", value/4, value/4-1000)
+
+	block, err := bc.NewBlockTemplate("miner")
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Transactions = append(block.Transactions, parent, child)
+	if err := bc.AddBlock(solve(t, block)); err != nil {
+		t.Fatal(err)
+	}
+	return parent, child
+}
+
+func TestLiveUTXOSetMatchesRebuild(t *testing.T) {
+	store := newMemStorage()
+	bc, err := NewBlockchain(store, RegtestParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := generate(t, bc, 101, "miner")
+	parent, child := connectSpendingBlock(t, bc, blocks[0].Transactions[0])
+
+	live := bc.UTXOSet
+	if live.FindUTXO(parent.TxHash, 0) != nil || live.FindUTXO(parent.TxHash, 2) != nil {
+		t.Fatal("outputs spent in the same block are still unspent")
+	}
+	if live.FindUTXO(parent.TxHash, 1) == nil || live.FindUTXO(parent.TxHash, 3) == nil {
+		t.Fatal("unspent outputs of the parent are missing")
+	}
+	if utxo := live.FindUTXO(child.TxHash, 1); utxo == nil || utxo.Height != 102 || utxo.IsCoinbase {
+		t.Fatalf("child output recorded as %+v", utxo)
+	}
+
+	bc.rebuildUTXOSet()
+	requireSameUTXOs(t, live, bc.UTXOSet)
+
+	// A restart rebuilds the set from storage
+	bc.Close()
+	reloaded, err := NewBlockchain(store, RegtestParams())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reloaded.Close()
+	requireSameUTXOs(t, live, reloaded.UTXOSet)
+}
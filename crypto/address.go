@@ -9,6 +9,9 @@ import (
 	"golang.org/x/crypto/ripemd160"
 )
 
+// MainnetVersion is the address version byte used on mainnet
+const MainnetVersion = byte(0x01)
+
 // Address represents a VOIDEX network address
 // Format: VDX + Base58(Hash160(PublicKey) + Checksum)
 // Example: VDX1ABC123...
@@ -37,11 +40,16 @@ func NewAddressFromHash160(hash160 [20]byte) *Address {
 	return &Address{hash160: hash160}
 }
 
-// String returns the VOIDEX address in standard format
+// String returns the VOIDEX mainnet address in standard format
 func (a *Address) String() string {
-	// Create payload: version byte (0x01 for mainnet) + hash160
+	return a.Encode(MainnetVersion)
+}
+
+// Encode returns the address for the network with the given version byte
+func (a *Address) Encode(version byte) string {
+	// Create payload: version byte + hash160
 	payload := make([]byte, 21)
-	payload[0] = 0x01 // VOIDEX mainnet version byte
+	payload[0] = version
 	copy(payload[1:], a.hash160[:])
 	
 	// Calculate checksum: first 4 bytes of SHA256(SHA256(payload))
@@ -55,8 +63,13 @@ func (a *Address) String() string {
 	return "VDX" + base58Encoded
 }
 
-// FromString parses a VOIDEX address string back to an Address object
+// FromString parses a VOIDEX mainnet address string back to an Address object
 func FromString(addressStr string) (*Address, error) {
+	return FromStringWithVersion(addressStr, MainnetVersion)
+}
+
+// FromStringWithVersion parses an address, requiring the given network version byte
+func FromStringWithVersion(addressStr string, version byte) (*Address, error) {
 	// Remove "VDX" prefix
 	if len(addressStr) < 3 || addressStr[:3] != "VDX" {
 		return nil, fmt.Errorf("invalid address prefix: expected VDX, got %s", addressStr[:3])
@@ -80,8 +93,8 @@ func FromString(addressStr string) (*Address, error) {
 	}
 	
 	// Verify version byte
-	if payload[0] != 0x01 {
-		return nil, fmt.Errorf("invalid address version: expected 0x01, got %02x", payload[0])
+	if payload[0] != version {
+		return nil, fmt.Errorf("invalid address version: expected %02x, got %02x", version, payload[0])
 	}
 	
 	// Extract hash160
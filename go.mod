@@ -2,8 +2,8 @@ module github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol
 
 go 1.20
 
-require (
-    github.com/syndtr/goleveldb v1.0.0
-)
+require github.com/syndtr/goleveldb v1.0.0
+
+require github.com/golang/snappy v0.0.0-20180518054509-2e65f85255db // indirect
 
 replace github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol => ./
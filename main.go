@@ -1,32 +1,69 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 
+	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/consensus"
 	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/core"
 	"github.com/Mrcubys/VOIDEX-Network---Layer-1-Blockchain-Protocol/storage"
 )
 
 func main() {
-	fmt.Println("Starting VOIDEX Network Layer-1 Blockchain Protocol...")
+	network := flag.String("network", core.NETWORK_MAINNET, "network to join: mainnet, testnet, regtest or devnet")
+	chainSpec := flag.String("chainspec", "", "run a custom network from a JSON chain spec instead of -network")
+	dataDir := flag.String("datadir", "", "blockchain data directory (defaults to the network's)")
+	upgrades := flag.String("upgrades", "", "regtest only: override upgrade heights, e.g. output_checks=10,difficulty_check=20")
+	flag.Parse()
+
+	var params *core.ChainParams
+	var err error
+	if *chainSpec != "" {
+		params, err = core.LoadChainSpec(*chainSpec)
+	} else {
+		params, err = core.GetChainParams(*network)
+	}
+	if err != nil {
+		log.Fatalf("Invalid network: %v", err)
+	}
+	if *upgrades != "" {
+		overrides, err := consensus.ParseUpgradeSchedule(*upgrades)
+		if err != nil {
+			log.Fatalf("Invalid upgrades: %v", err)
+		}
+		if err := params.OverrideUpgrades(overrides); err != nil {
+			log.Fatalf("Invalid upgrades: %v", err)
+		}
+	}
+	if *dataDir == "" {
+		*dataDir = params.DataDir
+	}
+
+	fmt.Printf("Starting VOIDEX Network Layer-1 Blockchain Protocol (%s)...\n", params.Name)
 
 	// Initialize storage
-	dbPath := "./blockchain_data"
-	store, err := storage.NewLevelDBStorage(dbPath)
+	store, err := storage.NewLevelDBStorage(*dataDir)
 	if err != nil {
 		log.Fatalf("Failed to initialize storage: %v", err)
 	}
 	defer store.Close()
 
 	// Create blockchain instance
-	minerAddress := "VDX1ABC123..." // Replace with your address
-	blockchain, err := core.NewBlockchain(store, minerAddress)
+	blockchain, err := core.NewBlockchain(store, params)
 	if err != nil {
 		log.Fatalf("Failed to create blockchain: %v", err)
 	}
+	defer blockchain.Close()
 
 	fmt.Printf("Blockchain initialized successfully!\n")
+	fmt.Printf("Network: %s (chain %s, magic 0x%08X, p2p port %d, rpc port %d)\n",
+		params.Name, params.Genesis.ChainID, params.P2PMagic, params.DefaultP2PPort, params.DefaultRPCPort)
+	fmt.Printf("Upgrades: %s\n", params.Genesis.Upgrades)
+	for _, deployment := range blockchain.GetDeployments() {
+		fmt.Printf("Deployment %s (bit %d): %s, %d/%d signals this window\n",
+			deployment.Name, deployment.Bit, deployment.State, deployment.Signals, deployment.Threshold)
+	}
 	fmt.Printf("Total blocks: %d\n", len(blockchain.Blocks))
 	fmt.Printf("Current difficulty: 0x%X\n", blockchain.Difficulty)
 